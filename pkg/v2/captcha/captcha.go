@@ -1,9 +1,11 @@
 // Package captcha provides a client for Cloudflare Turnstile Captcha.
 //
 // The package allows you to create and verify captchas of different types.
-// Currently, it supports two types of captchas: Turnstile and Testing.
-// The Turnstile captcha is a real captcha that requires verification,
-// while the Testing captcha is a dummy captcha used for testing purposes.
+// Currently, it supports three types of captchas: Turnstile, Testing and
+// TestingFail. The Turnstile captcha is a real captcha that requires
+// verification, while the Testing captcha is a dummy captcha used for testing
+// purposes. The TestingFail captcha always fails verification, which is useful
+// to test the failure path of handlers.
 //
 // Each captcha is represented by a Captcha struct, which contains the
 // necessary information for captcha verification, such as the site key,
@@ -17,6 +19,9 @@
 // and verifies the captcha based on its type. If the captcha type is not
 // supported, the method returns an error.
 //
+// Additional backends can be added by implementing the Provider interface and
+// registering a factory with Register under the name used as Captcha Type.
+//
 // Example Usage:
 //
 //	cfToken := c.FormValue("cf-turnstile-response")
@@ -31,9 +36,9 @@
 package captcha
 
 import (
+	"context"
 	"fmt"
-
-	"github.com/Talk-Point/go-toolkit/pkg/v2/captcha/turnstile"
+	"strings"
 )
 
 type CaptchaType int
@@ -41,10 +46,29 @@ type CaptchaType int
 const (
 	Turnstile CaptchaType = iota
 	Testing
+	TestingFail
 )
 
+var captchaTypeNames = [...]string{"Turnstile", "Testing", "TestingFail"}
+
+// String returns the name of the captcha type, or "Unknown(n)" for values
+// that are not defined.
 func (ct CaptchaType) String() string {
-	return [...]string{"Turnstile", "Testing"}[ct]
+	if ct < 0 || int(ct) >= len(captchaTypeNames) {
+		return fmt.Sprintf("Unknown(%d)", int(ct))
+	}
+	return captchaTypeNames[ct]
+}
+
+// CaptchaTypeFromString returns the captcha type with the given name, e.g.
+// from a config file. The name is matched case-insensitively.
+func CaptchaTypeFromString(s string) (CaptchaType, error) {
+	for i, name := range captchaTypeNames {
+		if strings.EqualFold(name, s) {
+			return CaptchaType(i), nil
+		}
+	}
+	return 0, fmt.Errorf("Captcha type not supported: %s", s)
 }
 
 type Captcha struct {
@@ -55,13 +79,31 @@ type Captcha struct {
 }
 
 func (c *Captcha) Verify(token string, ip string) error {
-	if c.Type == Turnstile.String() {
-		return turnstile.VerifyRequest(c.Secret, token, ip)
-	} else if c.Type == Testing.String() {
-		return nil
+	return c.VerifyContext(context.Background(), token, ip)
+}
+
+// VerifyContext verifies the token using the provider registered for the
+// Type of the captcha.
+func (c *Captcha) VerifyContext(ctx context.Context, token string, ip string) error {
+	factory, ok := lookupProvider(c.Type)
+	if !ok {
+		return fmt.Errorf("Captcha type not supported: %s", c.Type)
 	}
+	return factory(c.SiteKey, c.Secret).Verify(ctx, token, ip)
+}
 
-	return fmt.Errorf("Captcha type not supported: %s", c.Type)
+// NewCaptcha creates a captcha of the given type. It returns an error for
+// types that are not defined.
+func NewCaptcha(t CaptchaType, siteKey string, secret string) (*Captcha, error) {
+	switch t {
+	case Turnstile:
+		return NewCaptchaTurnstile(siteKey, secret), nil
+	case Testing:
+		return NewCaptchaTesting(siteKey, secret), nil
+	case TestingFail:
+		return NewCaptchaTestingFail(siteKey, secret), nil
+	}
+	return nil, fmt.Errorf("Captcha type not supported: %s", t)
 }
 
 func NewCaptchaTurnstile(siteKey string, secret string) *Captcha {
@@ -81,3 +123,12 @@ func NewCaptchaTesting(siteKey string, secret string) *Captcha {
 		Type:     Testing.String(),
 	}
 }
+
+func NewCaptchaTestingFail(siteKey string, secret string) *Captcha {
+	return &Captcha{
+		IsActive: true,
+		SiteKey:  siteKey,
+		Secret:   secret,
+		Type:     TestingFail.String(),
+	}
+}
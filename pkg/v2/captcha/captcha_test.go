@@ -1,7 +1,10 @@
 package captcha
 
 import (
+	"errors"
 	"testing"
+
+	"github.com/Talk-Point/go-toolkit/pkg/v2/captcha/turnstile"
 )
 
 func TestCaptcha(t *testing.T) {
@@ -22,4 +25,71 @@ func TestCaptcha(t *testing.T) {
 			t.Errorf("Expected nil, got %s", err)
 		}
 	})
+
+	t.Run("TestingFail", func(t *testing.T) {
+		captcha := NewCaptchaTestingFail("sitekey", "secret")
+		if captcha.Type != "TestingFail" {
+			t.Errorf("Expected TestingFail, got %s", captcha.Type)
+		}
+		err := captcha.Verify("token", "ip")
+		if !errors.Is(err, turnstile.ErrVerificationFailed) {
+			t.Errorf("Expected ErrVerificationFailed, got %v", err)
+		}
+	})
+}
+
+func TestNewCaptcha(t *testing.T) {
+	for _, name := range []string{"Turnstile", "Testing", "TestingFail"} {
+		t.Run(name, func(t *testing.T) {
+			ct, err := CaptchaTypeFromString(name)
+			if err != nil {
+				t.Fatalf("Expected nil, got %s", err)
+			}
+			if ct.String() != name {
+				t.Errorf("Expected %s, got %s", name, ct)
+			}
+			captcha, err := NewCaptcha(ct, "sitekey", "secret")
+			if err != nil {
+				t.Fatalf("Expected nil, got %s", err)
+			}
+			if captcha.Type != name || captcha.SiteKey != "sitekey" || captcha.Secret != "secret" {
+				t.Errorf("Unexpected captcha: %+v", captcha)
+			}
+		})
+	}
+
+	t.Run("Case insensitive", func(t *testing.T) {
+		if ct, err := CaptchaTypeFromString("turnstile"); err != nil || ct != Turnstile {
+			t.Errorf("Expected Turnstile, got %v, %v", ct, err)
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		if _, err := CaptchaTypeFromString("recaptcha"); err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+		if _, err := NewCaptcha(CaptchaType(42), "sitekey", "secret"); err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+	})
+}
+
+func TestCaptchaTypeString(t *testing.T) {
+	tests := []struct {
+		input    CaptchaType
+		expected string
+	}{
+		{Turnstile, "Turnstile"},
+		{TestingFail, "TestingFail"},
+		{CaptchaType(3), "Unknown(3)"},
+		{CaptchaType(-1), "Unknown(-1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := tt.input.String(); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
 }
@@ -0,0 +1,70 @@
+package captcha
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Talk-Point/go-toolkit/pkg/v2/captcha/turnstile"
+)
+
+// Provider verifies captcha tokens of a specific backend.
+type Provider interface {
+	Verify(ctx context.Context, token string, ip string) error
+}
+
+// ProviderFactory creates a Provider for the given site key and secret.
+type ProviderFactory func(siteKey string, secret string) Provider
+
+var (
+	providers     = map[string]ProviderFactory{}
+	providersLock sync.RWMutex
+)
+
+// Register makes a provider available under the given name. A Captcha whose
+// Type equals the name delegates verification to the provider. Registering a
+// name twice replaces the previous factory.
+func Register(name string, factory ProviderFactory) {
+	providersLock.Lock()
+	defer providersLock.Unlock()
+	providers[name] = factory
+}
+
+func lookupProvider(name string) (ProviderFactory, bool) {
+	providersLock.RLock()
+	defer providersLock.RUnlock()
+	factory, ok := providers[name]
+	return factory, ok
+}
+
+func init() {
+	Register(Turnstile.String(), func(siteKey string, secret string) Provider {
+		return &turnstileProvider{secret: secret}
+	})
+	Register(Testing.String(), func(siteKey string, secret string) Provider {
+		return &testingProvider{}
+	})
+	Register(TestingFail.String(), func(siteKey string, secret string) Provider {
+		return &testingFailProvider{}
+	})
+}
+
+type turnstileProvider struct {
+	secret string
+}
+
+func (p *turnstileProvider) Verify(ctx context.Context, token string, ip string) error {
+	_, err := turnstile.VerifyRequestContext(ctx, p.secret, token, ip, turnstile.VerifyOptions{})
+	return err
+}
+
+type testingProvider struct{}
+
+func (p *testingProvider) Verify(ctx context.Context, token string, ip string) error {
+	return nil
+}
+
+type testingFailProvider struct{}
+
+func (p *testingFailProvider) Verify(ctx context.Context, token string, ip string) error {
+	return turnstile.ErrVerificationFailed
+}
@@ -0,0 +1,38 @@
+package captcha
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type memoryProvider struct {
+	secret string
+	tokens map[string]bool
+}
+
+func (p *memoryProvider) Verify(ctx context.Context, token string, ip string) error {
+	if p.secret != "secret" || !p.tokens[token] {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
+func TestProvider(t *testing.T) {
+	Register("Memory", func(siteKey string, secret string) Provider {
+		return &memoryProvider{secret: secret, tokens: map[string]bool{"valid": true}}
+	})
+
+	captcha := &Captcha{IsActive: true, SiteKey: "sitekey", Secret: "secret", Type: "Memory"}
+	if err := captcha.Verify("valid", "ip"); err != nil {
+		t.Errorf("Expected nil, got %s", err)
+	}
+	if err := captcha.Verify("invalid", "ip"); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+
+	captcha = &Captcha{Type: "Unknown"}
+	if err := captcha.Verify("valid", "ip"); err == nil {
+		t.Errorf("Expected error for unsupported type, got nil")
+	}
+}
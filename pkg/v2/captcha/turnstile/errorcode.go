@@ -0,0 +1,63 @@
+package turnstile
+
+// ErrorCode is an error code documented for the siteverify endpoint.
+type ErrorCode int
+
+const (
+	// ErrorCodeUnknown is used for codes that are not documented.
+	ErrorCodeUnknown ErrorCode = iota
+	ErrorCodeMissingInputSecret
+	ErrorCodeInvalidInputSecret
+	ErrorCodeMissingInputResponse
+	ErrorCodeInvalidInputResponse
+	ErrorCodeInvalidWidgetID
+	ErrorCodeInvalidParsedSecret
+	ErrorCodeBadRequest
+	ErrorCodeTimeoutOrDuplicate
+	ErrorCodeInternalError
+)
+
+var errorCodeNames = map[string]ErrorCode{
+	"missing-input-secret":   ErrorCodeMissingInputSecret,
+	"invalid-input-secret":   ErrorCodeInvalidInputSecret,
+	"missing-input-response": ErrorCodeMissingInputResponse,
+	"invalid-input-response": ErrorCodeInvalidInputResponse,
+	"invalid-widget-id":      ErrorCodeInvalidWidgetID,
+	"invalid-parsed-secret":  ErrorCodeInvalidParsedSecret,
+	"bad-request":            ErrorCodeBadRequest,
+	"timeout-or-duplicate":   ErrorCodeTimeoutOrDuplicate,
+	"internal-error":         ErrorCodeInternalError,
+}
+
+// ResponseErrorCode is an error code of a Response. Raw holds the code as
+// returned by Cloudflare, so undocumented codes are preserved.
+type ResponseErrorCode struct {
+	Code ErrorCode
+	Raw  string
+}
+
+// ParseErrorCode maps an error code returned by Cloudflare to an ErrorCode.
+// Undocumented codes map to ErrorCodeUnknown.
+func ParseErrorCode(raw string) ResponseErrorCode {
+	return ResponseErrorCode{Code: errorCodeNames[raw], Raw: raw}
+}
+
+// ParsedErrorCodes returns the ErrorCodes of the response parsed with
+// ParseErrorCode.
+func (r *Response) ParsedErrorCodes() []ResponseErrorCode {
+	codes := make([]ResponseErrorCode, len(r.ErrorCodes))
+	for i, raw := range r.ErrorCodes {
+		codes[i] = ParseErrorCode(raw)
+	}
+	return codes
+}
+
+// HasErrorCode reports whether the response contains the error code.
+func (r *Response) HasErrorCode(code ErrorCode) bool {
+	for _, c := range r.ParsedErrorCodes() {
+		if c.Code == code {
+			return true
+		}
+	}
+	return false
+}
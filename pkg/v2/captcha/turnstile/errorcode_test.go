@@ -0,0 +1,51 @@
+package turnstile
+
+import (
+	"testing"
+)
+
+func TestParseErrorCode(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected ErrorCode
+	}{
+		{"missing-input-secret", ErrorCodeMissingInputSecret},
+		{"invalid-input-secret", ErrorCodeInvalidInputSecret},
+		{"missing-input-response", ErrorCodeMissingInputResponse},
+		{"invalid-input-response", ErrorCodeInvalidInputResponse},
+		{"invalid-widget-id", ErrorCodeInvalidWidgetID},
+		{"invalid-parsed-secret", ErrorCodeInvalidParsedSecret},
+		{"bad-request", ErrorCodeBadRequest},
+		{"timeout-or-duplicate", ErrorCodeTimeoutOrDuplicate},
+		{"internal-error", ErrorCodeInternalError},
+		{"new-error", ErrorCodeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			code := ParseErrorCode(tt.raw)
+			if code.Code != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, code.Code)
+			}
+			if code.Raw != tt.raw {
+				t.Errorf("Expected raw code %s, got %s", tt.raw, code.Raw)
+			}
+		})
+	}
+}
+
+func TestResponseErrorCodes(t *testing.T) {
+	mockSiteVerify(t, Response{Success: false, ErrorCodes: []string{"timeout-or-duplicate", "new-error"}})
+
+	resp, err := VerifyRequestDetailed("secret", "token", "ip", VerifyOptions{})
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	codes := resp.ParsedErrorCodes()
+	if len(codes) != 2 || codes[0].Code != ErrorCodeTimeoutOrDuplicate || codes[1].Code != ErrorCodeUnknown || codes[1].Raw != "new-error" {
+		t.Errorf("Unexpected error codes: %v", codes)
+	}
+	if !resp.HasErrorCode(ErrorCodeTimeoutOrDuplicate) || resp.HasErrorCode(ErrorCodeBadRequest) {
+		t.Errorf("Unexpected HasErrorCode result")
+	}
+}
@@ -0,0 +1,68 @@
+package turnstile
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned if a verification is rejected by the local rate
+// limit without calling Cloudflare.
+var ErrRateLimited = errors.New("verification rate limited")
+
+var (
+	limiter     *tokenBucket
+	limiterLock sync.Mutex
+)
+
+// SetRateLimit throttles outbound verification calls using a token bucket that
+// allows requestsPerSecond on average with bursts of up to burst requests.
+// Verifications exceeding the limit fail with ErrRateLimited. A
+// requestsPerSecond of 0 or less disables the limit.
+func SetRateLimit(requestsPerSecond float64, burst int) {
+	limiterLock.Lock()
+	defer limiterLock.Unlock()
+	if requestsPerSecond <= 0 {
+		limiter = nil
+		return
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	limiter = &tokenBucket{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// allowRequest reports whether a verification may be sent.
+func allowRequest() bool {
+	limiterLock.Lock()
+	defer limiterLock.Unlock()
+	if limiter == nil {
+		return true
+	}
+	return limiter.allow(time.Now())
+}
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) allow(now time.Time) bool {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
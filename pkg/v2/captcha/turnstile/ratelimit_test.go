@@ -0,0 +1,37 @@
+package turnstile
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	mockSiteVerify(t, Response{Success: true})
+	SetRateLimit(0.001, 2)
+	defer SetRateLimit(0, 0)
+
+	for i := 0; i < 2; i++ {
+		if err := VerifyRequest("secret", "token", "ip"); err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+	}
+	if err := VerifyRequest("secret", "token", "ip"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := &tokenBucket{rate: 10, burst: 1, tokens: 1, last: now}
+
+	if !b.allow(now) {
+		t.Errorf("Expected first request to be allowed")
+	}
+	if b.allow(now) {
+		t.Errorf("Expected second request to be limited")
+	}
+	if !b.allow(now.Add(100 * time.Millisecond)) {
+		t.Errorf("Expected request to be allowed after refill")
+	}
+}
@@ -2,24 +2,104 @@ package turnstile
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 )
 
+var (
+	// ErrVerificationFailed is returned if Cloudflare rejects the token.
+	ErrVerificationFailed = errors.New("verification failed")
+	// ErrActionMismatch is returned if the action of the response does not match
+	// the expected action.
+	ErrActionMismatch = errors.New("verification action mismatch")
+)
+
+// siteVerifyURL is a variable so tests can point it to a local server.
+var siteVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+
+// VerifyOptions holds optional parameters of a verification.
+type VerifyOptions struct {
+	// Action is the expected action of the widget. If set, the verification
+	// fails with ErrActionMismatch if the response contains a different action.
+	Action string
+	// IdempotencyKey allows retrying the verification of the same token.
+	IdempotencyKey string
+}
+
+// Response is the response of the siteverify endpoint.
+type Response struct {
+	Success     bool     `json:"success"`
+	ErrorCodes  []string `json:"error-codes"`
+	ChallengeTs string   `json:"challenge_ts"`
+	Hostname    string   `json:"hostname"`
+	Action      string   `json:"action"`
+	CData       string   `json:"cdata"`
+}
+
+// VerifyOutcome describes the result of a verification. It is passed to
+// VerifyHook after each verification.
+type VerifyOutcome struct {
+	Success    bool
+	ErrorCodes []string
+	Err        error
+	Duration   time.Duration
+}
+
+// VerifyHook, if set, is called after each verification, e.g. to record metrics.
+var VerifyHook func(outcome VerifyOutcome)
+
 func VerifyRequest(secret string, token string, ip string) error {
+	_, err := VerifyRequestDetailed(secret, token, ip, VerifyOptions{})
+	return err
+}
+
+// VerifyRequestDetailed verifies the token and returns the full response of the
+// siteverify endpoint. The response is returned together with the error if the
+// verification failed.
+func VerifyRequestDetailed(secret string, token string, ip string, opts VerifyOptions) (*Response, error) {
+	return VerifyRequestContext(context.Background(), secret, token, ip, opts)
+}
+
+// VerifyRequestContext works like VerifyRequestDetailed but uses the context
+// for the request to the siteverify endpoint.
+func VerifyRequestContext(ctx context.Context, secret string, token string, ip string, opts VerifyOptions) (*Response, error) {
+	start := time.Now()
+	resp, err := verify(ctx, secret, token, ip, opts)
+	if VerifyHook != nil {
+		outcome := VerifyOutcome{
+			Success:  err == nil,
+			Err:      err,
+			Duration: time.Since(start),
+		}
+		if resp != nil {
+			outcome.ErrorCodes = resp.ErrorCodes
+		}
+		VerifyHook(outcome)
+	}
+	return resp, err
+}
+
+func verify(ctx context.Context, secret string, token string, ip string, opts VerifyOptions) (*Response, error) {
+	if !allowRequest() {
+		return nil, ErrRateLimited
+	}
+
 	formData := url.Values{}
 	formData.Set("secret", secret)
 	formData.Set("response", token)
 	formData.Set("remoteip", ip)
+	if opts.IdempotencyKey != "" {
+		formData.Set("idempotency_key", opts.IdempotencyKey)
+	}
 
-	const url = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
-
-	req, err := http.NewRequest("POST", url, bytes.NewBufferString(formData.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", siteVerifyURL, bytes.NewBufferString(formData.Encode()))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
@@ -27,22 +107,25 @@ func VerifyRequest(secret string, token string, ip string) error {
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var outcome map[string]interface{}
+	var outcome Response
 	if err := json.Unmarshal(body, &outcome); err != nil {
-		return err
+		return nil, err
 	}
 
-	if success, ok := outcome["success"].(bool); ok && success {
-		return nil
+	if !outcome.Success {
+		return &outcome, ErrVerificationFailed
+	}
+	if opts.Action != "" && outcome.Action != opts.Action {
+		return &outcome, ErrActionMismatch
 	}
-	return errors.New("verification failed")
+	return &outcome, nil
 }
@@ -0,0 +1,96 @@
+package turnstile
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func mockSiteVerify(t *testing.T, response Response) *http.Request {
+	t.Helper()
+	received := &http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		*received = *r
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	url := siteVerifyURL
+	siteVerifyURL = server.URL
+	t.Cleanup(func() { siteVerifyURL = url })
+	return received
+}
+
+func TestVerifyRequest(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockSiteVerify(t, Response{Success: true})
+		if err := VerifyRequest("secret", "token", "ip"); err != nil {
+			t.Errorf("Expected nil, got %s", err)
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		mockSiteVerify(t, Response{Success: false})
+		if err := VerifyRequest("secret", "token", "ip"); !errors.Is(err, ErrVerificationFailed) {
+			t.Errorf("Expected ErrVerificationFailed, got %v", err)
+		}
+	})
+}
+
+func TestVerifyRequestDetailed(t *testing.T) {
+	t.Run("Matching action", func(t *testing.T) {
+		received := mockSiteVerify(t, Response{Success: true, Action: "login"})
+		resp, err := VerifyRequestDetailed("secret", "token", "ip", VerifyOptions{Action: "login", IdempotencyKey: "key"})
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+		if resp.Action != "login" {
+			t.Errorf("Expected action login, got %s", resp.Action)
+		}
+		if received.PostForm.Get("idempotency_key") != "key" {
+			t.Errorf("Expected idempotency_key to be sent, got %s", received.PostForm.Get("idempotency_key"))
+		}
+	})
+
+	t.Run("Mismatching action", func(t *testing.T) {
+		mockSiteVerify(t, Response{Success: true, Action: "signup"})
+		_, err := VerifyRequestDetailed("secret", "token", "ip", VerifyOptions{Action: "login"})
+		if !errors.Is(err, ErrActionMismatch) {
+			t.Errorf("Expected ErrActionMismatch, got %v", err)
+		}
+	})
+}
+
+func TestVerifyHook(t *testing.T) {
+	var outcomes []VerifyOutcome
+	VerifyHook = func(outcome VerifyOutcome) {
+		outcomes = append(outcomes, outcome)
+	}
+	defer func() { VerifyHook = nil }()
+
+	mockSiteVerify(t, Response{Success: true})
+	if err := VerifyRequest("secret", "token", "ip"); err != nil {
+		t.Errorf("Expected nil, got %s", err)
+	}
+
+	mockSiteVerify(t, Response{Success: false, ErrorCodes: []string{"invalid-input-response"}})
+	if err := VerifyRequest("secret", "token", "ip"); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+
+	if len(outcomes) != 2 {
+		t.Fatalf("Expected 2 outcomes, got %d", len(outcomes))
+	}
+	if !outcomes[0].Success || outcomes[0].Err != nil {
+		t.Errorf("Expected successful outcome, got %+v", outcomes[0])
+	}
+	if outcomes[1].Success || !errors.Is(outcomes[1].Err, ErrVerificationFailed) {
+		t.Errorf("Expected failed outcome, got %+v", outcomes[1])
+	}
+	if len(outcomes[1].ErrorCodes) != 1 || outcomes[1].ErrorCodes[0] != "invalid-input-response" {
+		t.Errorf("Expected error code, got %v", outcomes[1].ErrorCodes)
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"sync"
+)
+
+// resolutionCache maps a name within a list of commands to the resolved
+// command, see CacheResolution. An entry records where the command was found,
+// so it is only used while the list still holds the same command at the same
+// position, e.g. replacing, adding or removing commands invalidates it.
+type resolutionCache[T any] struct {
+	lock    sync.RWMutex
+	entries map[resolutionKey[T]]resolution[T]
+}
+
+type resolutionKey[T any] struct {
+	commands **Command[T]
+	name     string
+}
+
+type resolution[T any] struct {
+	cmd   *Command[T]
+	use   string
+	index int
+	len   int
+}
+
+func (r *resolutionCache[T]) get(commands []*Command[T], name string) (*Command[T], bool) {
+	if len(commands) == 0 {
+		return nil, false
+	}
+	r.lock.RLock()
+	e, ok := r.entries[resolutionKey[T]{&commands[0], name}]
+	r.lock.RUnlock()
+	if !ok || e.len != len(commands) || commands[e.index] != e.cmd || e.cmd.Use != e.use {
+		return nil, false
+	}
+	return e.cmd, true
+}
+
+func (r *resolutionCache[T]) put(commands []*Command[T], name string, cmd *Command[T]) {
+	index := -1
+	for i, c := range commands {
+		if c == cmd {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.entries == nil {
+		r.entries = map[resolutionKey[T]]resolution[T]{}
+	}
+	r.entries[resolutionKey[T]{&commands[0], name}] = resolution[T]{cmd: cmd, use: cmd.Use, index: index, len: len(commands)}
+}
+
+// InvalidateCache clears the cache of resolved commands, see CacheResolution.
+func (c *CliRoot[T]) InvalidateCache() {
+	if c.cache == nil {
+		return
+	}
+	c.cache.lock.Lock()
+	defer c.cache.lock.Unlock()
+	c.cache.entries = nil
+}
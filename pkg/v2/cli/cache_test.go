@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+)
+
+func resolutionCommands(n int) []*Command[*Context] {
+	users := []*Command[*Context]{}
+	for i := 0; i < n; i++ {
+		users = append(users, &Command[*Context]{
+			Use: fmt.Sprintf("cmd%03d", i),
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return nil, nil
+			},
+		})
+	}
+	return []*Command[*Context]{
+		{Use: "version"},
+		{Use: "users", Commands: users},
+	}
+}
+
+func TestCacheResolution(t *testing.T) {
+	uncached := Cli[*Context](&Context{}, resolutionCommands(20))
+	cached := Cli[*Context](&Context{}, uncached.Commands)
+	cached.CacheResolution = true
+
+	for i := 0; i < 2; i++ {
+		for _, args := range [][]string{{"users", "cmd003"}, {"users", "cmd019", "-x"}, {"version"}} {
+			expected, _, err := uncached.Resolve(args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			cmd, _, err := cached.Resolve(args)
+			if err != nil || cmd != expected {
+				t.Errorf("Expected %s, got %v, %v", expected.Use, cmd, err)
+			}
+		}
+	}
+
+	t.Run("Replace", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, resolutionCommands(2))
+		c.CacheResolution = true
+		if _, err := c.RunWithCommand("users cmd001"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		replacement := &Command[*Context]{Use: "cmd001", Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+			return &DataMessage{Message: "replaced"}, nil
+		}}
+		c.Commands[1].Commands[1] = replacement
+		if cmd, _, _ := c.Resolve([]string{"users", "cmd001"}); cmd != replacement {
+			t.Errorf("Expected the replaced command, got %v", cmd)
+		}
+		if data, _ := c.RunWithCommand("users cmd001"); data == nil || data.(*DataMessage).Message != "replaced" {
+			t.Errorf("Expected the replaced command to run, got %v", data)
+		}
+	})
+
+	t.Run("Rename", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, resolutionCommands(2))
+		c.CacheResolution = true
+		c.Resolve([]string{"version"})
+		c.Commands[0].Use = "about"
+		if cmd, _, _ := c.Resolve([]string{"version"}); cmd != nil {
+			t.Errorf("Expected the renamed command not to resolve, got %s", cmd.Use)
+		}
+	})
+
+	t.Run("Append", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, resolutionCommands(2))
+		c.CacheResolution = true
+		c.PrefixMatching = true
+		if cmd, _, _ := c.Resolve([]string{"v"}); cmd != c.Commands[0] {
+			t.Fatalf("Expected the prefix to resolve to version")
+		}
+		c.Commands = append(c.Commands, &Command[*Context]{Use: "validate"})
+		if _, _, err := c.Resolve([]string{"v"}); err == nil {
+			t.Errorf("Expected the prefix to be ambiguous after adding a command")
+		}
+	})
+
+	t.Run("Invalidate", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, resolutionCommands(2))
+		c.CacheResolution = true
+		c.Resolve([]string{"users"})
+		// renaming an earlier command in place is not seen by the cache
+		other := c.Commands[0]
+		other.Use = "users"
+		c.InvalidateCache()
+		if cmd, _, _ := c.Resolve([]string{"users"}); cmd != other {
+			t.Errorf("Expected InvalidateCache to clear the cache")
+		}
+	})
+}
+
+func BenchmarkResolve(b *testing.B) {
+	for _, cache := range []bool{false, true} {
+		b.Run(fmt.Sprintf("cache=%t", cache), func(b *testing.B) {
+			c := Cli[*Context](&Context{}, resolutionCommands(200))
+			c.PrefixMatching = true
+			c.CacheResolution = cache
+			args := []string{"users", "cmd199", "-name", "max"}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c.Resolve(args)
+			}
+		})
+	}
+}
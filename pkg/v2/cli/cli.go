@@ -74,10 +74,19 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"runtime/debug"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/Talk-Point/go-toolkit/pkg/v2/formatter"
 )
 
 // Format formats the given data and returns a string representation.
@@ -89,12 +98,19 @@ type Formatter interface {
 }
 
 // JSONFormatter implements Formatter to output data in JSON format.
-type JSONFormatter struct{}
+type JSONFormatter struct {
+	// Placeholder, if set, is returned instead of an error when the data
+	// cannot be marshaled.
+	Placeholder string
+}
 
 func (j *JSONFormatter) Format(data interface{}) (string, error) {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
-		return "", err
+		if j.Placeholder != "" {
+			return j.Placeholder, nil
+		}
+		return "", fmt.Errorf("error formatting %T as json: %w", data, err)
 	}
 	return string(jsonData), nil
 }
@@ -103,10 +119,20 @@ func (j *JSONFormatter) Type() string {
 	return "json"
 }
 
-type TextFormatter struct{}
+// TextFormatter implements Formatter to output data as plain text.
+//
+// If WrapWidth is greater than zero, long lines are wrapped at word boundaries
+// to that width. Set it to WrapAuto to wrap at the width of the terminal.
+type TextFormatter struct {
+	WrapWidth int
+}
 
 func (t *TextFormatter) Format(data interface{}) (string, error) {
-	return fmt.Sprintf("%v", data), nil
+	return t.wrap(fmt.Sprintf("%v", data)), nil
+}
+
+func (t *TextFormatter) wrap(s string) string {
+	return wrapText(s, resolveWidth(t.WrapWidth))
 }
 
 func (t *TextFormatter) Type() string {
@@ -124,24 +150,179 @@ type Data interface {
 // that can be formatted and displayed.
 type DataMessage struct {
 	Message string `json:"message"`
+	// Raw displays the message unchanged, e.g. for generated scripts that
+	// must not be wrapped.
+	Raw bool `json:"-"`
 }
 
-// Display simply returns the message as a string without further formatting,
-// regardless of the formatter used. This ensures that the message is displayed
-// as intended without modification.
+// Display returns the message as a string regardless of the formatter used.
+// Formatters with a WrapWidth wrap it unless Raw is set.
 func (d *DataMessage) Display(formatter Formatter) (string, error) {
+	if w, ok := formatter.(wrapper); ok && !d.Raw {
+		return w.wrap(d.Message), nil
+	}
 	return d.Message, nil
 }
 
 // DataList represents a structured list of items, each being a map of strings.
 // It is typically used to present a collection of similar data objects.
+//
+// If Columns is set, text output only renders those keys, in the given order.
+// Keys missing from an item are rendered as empty values. JSON output always
+// includes all keys.
+//
+// Total, Page and PageSize can be set by commands backed by paged APIs. They
+// are included in the JSON output and rendered as a footer in text output if
+// set.
+//
+// If GroupBy is set, the items are partitioned by the value of that key and
+// rendered as one section per group, in order of first appearance. Items
+// without the key are rendered last under UngroupedTitle. JSON output holds
+// the groups as an object keyed by the group value. CSV output and JSON Lines
+// are not sectioned, the group value is a column of each row instead.
+//
+// The keys of the JSON items are sorted. If OrderedJSON is set, the Columns
+// come first in the given order instead, e.g. for golden files matching the
+// text output.
 type DataList struct {
-	Title string              `json:"title"`
-	Items []map[string]string `json:"items"`
+	Title    string              `json:"title"`
+	Items    []map[string]string `json:"items"`
+	Columns  []string            `json:"-"`
+	Total    int                 `json:"total,omitempty"`
+	Page     int                 `json:"page,omitempty"`
+	PageSize int                 `json:"page_size,omitempty"`
+	GroupBy  string              `json:"-"`
+	// ShowIndex prepends a 1-based "#" column to the text, table and CSV
+	// output. If Page and PageSize are set, the index is the absolute position
+	// of the item across all pages.
+	ShowIndex bool `json:"-"`
+	// OrderedJSON marshals the keys of the JSON items in the order of the
+	// Columns, followed by the remaining keys sorted.
+	OrderedJSON bool `json:"-"`
 }
 
+// UngroupedTitle is the heading of the items without the GroupBy key.
+const UngroupedTitle = "ungrouped"
+
 func (d *DataList) Display(formatter Formatter) (string, error) {
-	return formatter.Format(d)
+	if d.ShowIndex && !isJSON(formatter) {
+		d = d.withIndex()
+	}
+	// CSV needs the same columns in every row, so the group key is a column
+	if d.GroupBy != "" && isCSV(formatter) {
+		return formatter.Format(d.withGroupColumn())
+	}
+	// JSON Lines stream the items ungrouped, each item contains its group key
+	if d.GroupBy == "" || formatter.Type() == "ndjson" {
+		if d.OrderedJSON && formatter.Type() == "json" {
+			return formatter.Format(&orderedList{
+				Title:    d.Title,
+				Items:    d.jsonItems(d.Items),
+				Total:    d.Total,
+				Page:     d.Page,
+				PageSize: d.PageSize,
+			})
+		}
+		return formatter.Format(d)
+	}
+
+	names, groups := d.groups()
+	if formatter.Type() == "json" {
+		jsonGroups := make(map[string]interface{}, len(groups))
+		for name, items := range groups {
+			jsonGroups[name] = d.jsonItems(items)
+		}
+		return formatter.Format(&groupedList{
+			Title:    d.Title,
+			Groups:   jsonGroups,
+			Total:    d.Total,
+			Page:     d.Page,
+			PageSize: d.PageSize,
+		})
+	}
+
+	a := []string{}
+	if d.Title != "" {
+		a = append(a, d.Title)
+	}
+	for _, name := range names {
+		v, err := formatter.Format(&DataList{
+			Title:   name,
+			Items:   groups[name],
+			Columns: d.Columns,
+		})
+		if err != nil {
+			return "", err
+		}
+		a = append(a, v)
+	}
+	if footer := d.footer(); footer != "" {
+		a = append(a, footer)
+	}
+	return strings.Join(a, "\n\n"), nil
+}
+
+// IndexColumn is the column added by ShowIndex.
+const IndexColumn = "#"
+
+// withIndex returns a copy of the list with the IndexColumn added to the
+// columns and items.
+func (d *DataList) withIndex() *DataList {
+	offset := 0
+	if d.Page > 0 && d.PageSize > 0 {
+		offset = (d.Page - 1) * d.PageSize
+	}
+
+	columns := d.Columns
+	if len(columns) == 0 {
+		columns = listColumns(d.Items)
+	}
+	indexed := *d
+	indexed.ShowIndex = false
+	indexed.Columns = append([]string{IndexColumn}, columns...)
+	indexed.Items = make([]map[string]string, len(d.Items))
+	for i, item := range d.Items {
+		c := make(map[string]string, len(item)+1)
+		for k, v := range item {
+			c[k] = v
+		}
+		c[IndexColumn] = strconv.Itoa(offset + i + 1)
+		indexed.Items[i] = c
+	}
+	return &indexed
+}
+
+// groups partitions the items by the GroupBy key. It returns the group names
+// in order of first appearance, with UngroupedTitle last.
+func (d *DataList) groups() ([]string, map[string][]map[string]string) {
+	names := []string{}
+	groups := map[string][]map[string]string{}
+	ungrouped := []map[string]string{}
+	for _, item := range d.Items {
+		name, ok := item[d.GroupBy]
+		if !ok {
+			ungrouped = append(ungrouped, item)
+			continue
+		}
+		if _, exists := groups[name]; !exists {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], item)
+	}
+	if len(ungrouped) > 0 {
+		names = append(names, UngroupedTitle)
+		groups[UngroupedTitle] = append(groups[UngroupedTitle], ungrouped...)
+	}
+	return names, groups
+}
+
+// groupedList is the JSON representation of a DataList with GroupBy.
+type groupedList struct {
+	Title    string                 `json:"title"`
+	Groups   map[string]interface{} `json:"groups"`
+	Total    int                    `json:"total,omitempty"`
+	Page     int                    `json:"page,omitempty"`
+	PageSize int                    `json:"page_size,omitempty"`
 }
 
 func (d *DataList) Error() string {
@@ -150,48 +331,179 @@ func (d *DataList) Error() string {
 	a = append(a, d.Title)
 
 	for _, item := range d.Items {
+		if len(d.Columns) > 0 {
+			for _, k := range d.Columns {
+				a = append(a, fmt.Sprintf("%s: %s", k, item[k]))
+			}
+			continue
+		}
 		for k, v := range item {
 			a = append(a, fmt.Sprintf("%s: %s", k, v))
 		}
 	}
 
+	if footer := d.footer(); footer != "" {
+		a = append(a, footer)
+	}
+
 	return strings.Join(a, "\n")
 }
 
+// footer returns the pagination metadata of the list or "" if none is set.
+func (d *DataList) footer() string {
+	a := []string{}
+	if d.Total != 0 {
+		a = append(a, fmt.Sprintf("total: %d", d.Total))
+	}
+	if d.Page != 0 {
+		a = append(a, fmt.Sprintf("page: %d", d.Page))
+	}
+	if d.PageSize != 0 {
+		a = append(a, fmt.Sprintf("page size: %d", d.PageSize))
+	}
+	return strings.Join(a, ", ")
+}
+
 // DataDetails holds detailed information about a single item, typically used
 // for displaying detailed views of a specific entity.
 type DataDetails struct {
 	Title string            `json:"title"`
 	Item  map[string]string `json:"item"`
+	// Keys, if set, orders the keys of the JSON item. The keys of Item not
+	// listed follow sorted.
+	Keys []string `json:"-"`
 }
 
 func (d *DataDetails) Display(formatter Formatter) (string, error) {
+	if len(d.Keys) > 0 && isJSON(formatter) {
+		return formatter.Format(&orderedDetails{Title: d.Title, Item: newOrderedMap(d.Item, d.Keys)})
+	}
 	return formatter.Format(d)
 }
 
 func (d *DataDetails) Error() string {
-	a := []string{}
-	a = append(a, d.Title)
-	for k, v := range d.Item {
-		a = append(a, fmt.Sprintf("%s: %s", k, v))
+	if len(d.Item) == 0 {
+		return d.Title
 	}
-	return strings.Join(a, "\n")
+	return d.Title + "\n" + formatter.FormatKV(d.Item, "\n")
 }
 
 // DataError is used to represent errors as data. This allows error messages to be formatted
 // and displayed using the same mechanisms as other data types.
+//
+// Err optionally holds the underlying error, so errors.Is and errors.As work
+// through the DataError. Code and Details are machine-readable information
+// included in the JSON output, e.g. CodeCommandNotFound.
 type DataError struct {
-	Message string `json:"error"`
+	Message string            `json:"error"`
+	Code    string            `json:"code,omitempty"`
+	Details map[string]string `json:"details,omitempty"`
+	Err     error             `json:"-"`
+}
+
+// CodeCommandNotFound is the Code of the error returned for unknown commands.
+// The unknown command is stored in the "token" detail.
+const CodeCommandNotFound = "command_not_found"
+
+// CodeAmbiguousCommand is the Code of the error returned for prefixes matching
+// several commands, see PrefixMatching. The prefix is stored in the "token"
+// detail, the comma separated matching commands in the "candidates" detail.
+const CodeAmbiguousCommand = "ambiguous_command"
+
+// CodeUnknownFlag is the Code of the error returned for flags not accepted by
+// the command, see StrictFlags. The flag is stored in the "flag" detail, the
+// comma separated valid flags in the "valid" detail.
+const CodeUnknownFlag = "unknown_flag"
+
+// checkFlags returns an error for the first flag in args that is not known.
+func checkFlags(known []string, args []string) error {
+	for _, arg := range args {
+		if arg == "--" {
+			return nil
+		}
+		if len(arg) < 2 || arg[0] != '-' || (arg[1] >= '0' && arg[1] <= '9') {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		found := false
+		for _, k := range known {
+			if k == name {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		valid := "none"
+		if len(known) > 0 {
+			valid = "-" + strings.Join(known, ", -")
+		}
+		return &DataError{
+			Message: fmt.Sprintf("unknown flag -%s, valid flags: %s", name, valid),
+			Code:    CodeUnknownFlag,
+			Details: map[string]string{"flag": name, "valid": strings.Join(known, ",")},
+		}
+	}
+	return nil
+}
+
+func commandNotFound(token string) *DataError {
+	return &DataError{
+		Message: "command " + token + " not found",
+		Code:    CodeCommandNotFound,
+		Details: map[string]string{"token": token},
+	}
 }
 
 func (d *DataError) Error() string {
 	return d.Message
 }
 
+func (d *DataError) Unwrap() error {
+	return d.Err
+}
+
 func (d *DataError) Display(formatter Formatter) (string, error) {
 	return formatter.Format(d)
 }
 
+// AsData converts an error returned by a command into the Data displayed for
+// it: the error itself if it is a *DataError or *DataValidationError, a
+// *DataValidationError for an error wrapping a *ValidationError and a
+// *DataError holding the message otherwise.
+func AsData(err error) Data {
+	switch e := err.(type) {
+	case *DataError:
+		return e
+	case *DataValidationError:
+		return e
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return &DataValidationError{
+			Message: err.Error(),
+			Code:    CodeValidationFailed,
+			Fields:  validationErr.Fields,
+			Err:     err,
+		}
+	}
+	return &DataError{
+		Message: err.Error(),
+		Err:     err,
+	}
+}
+
+// RetryPolicy describes how often a failing command is retried.
+type RetryPolicy struct {
+	// Attempts is the total number of times Run is called, including the first call.
+	Attempts int
+	// Backoff is the time to wait between two attempts.
+	Backoff time.Duration
+	// Retryable reports whether an error should be retried. If nil, all errors are retried.
+	Retryable func(error) bool
+}
+
 type Command[T any] struct {
 	Use      string
 	Short    string
@@ -199,71 +511,661 @@ type Command[T any] struct {
 	Run      func(cmd *Command[T], args []string, ctx T) (Data, error)
 	Commands []*Command[T]
 	Example  string
+	// Examples are example invocations rendered one per line with a "$ "
+	// prompt, see RenderExamples. They are listed after Example.
+	Examples []string
+	// Group is the heading the command is listed under in the help output.
+	Group string
+	// Retry, if set, retries Run according to the policy when it returns an error.
+	Retry *RetryPolicy
+	// DefaultFormatter, if set, replaces the formatter of the root for the
+	// output of the command, unless an output flag such as -json is passed.
+	DefaultFormatter Formatter
+	// KnownFlags are the names of the flags accepted by the command, without
+	// leading dashes. They are checked if StrictFlags is set on the root.
+	KnownFlags []string
+	// Flags describes the flags of the command for the help output and the
+	// completion scripts. The flags are known flags for StrictFlags as well.
+	Flags []FlagSpec
+	// ContextFunc, if set, derives the context passed to Run from the Ctx of
+	// the root, e.g. to open a database connection only for the commands
+	// needing it. It is called once before Run, a returned error aborts the
+	// command.
+	ContextFunc func(cmd *Command[T], base T) (T, error)
+
+	root *CliRoot[T]
+	ctx  context.Context
+}
+
+// Root returns the CliRoot executing the command. It gives access to global
+// flags such as DryRun. Each run is executed by its own copy of the root, so
+// the flags of one run are not seen by the next. Root returns nil if the
+// command is not being executed by a CliRoot.
+func (cmd *Command[T]) Root() *CliRoot[T] {
+	return cmd.root
+}
+
+// Context returns the context of the current execution of the command. It is
+// cancelled when the timeout set by the -timeout flag expires, long running
+// commands should stop then.
+func (cmd *Command[T]) Context() context.Context {
+	if cmd.ctx == nil {
+		return context.Background()
+	}
+	return cmd.ctx
+}
+
+// CommandLogEntry describes a single command invocation. It is passed to
+// CliRoot.Logger after the command has completed.
+type CommandLogEntry struct {
+	// Path is the resolved command path, e.g. "users create".
+	Path     string
+	Args     []string
+	Duration time.Duration
+	Err      error
 }
 
 type CliRoot[T any] struct {
+	// Name is the name of the executable used in completion scripts. It
+	// defaults to the base name of os.Args[0].
+	Name      string
 	Ctx       T
 	Commands  []*Command[T]
 	Formatter Formatter
+	// Logger is called after each command completes, if set.
+	Logger func(entry CommandLogEntry)
+	// ShowTiming appends the execution time of the command to the output.
+	ShowTiming bool
+	// DryRun is set if the -dry-run flag was passed. The framework does not
+	// skip execution, commands are expected to check it themselves.
+	DryRun bool
+	// Debug includes stack traces in errors caused by panics. It is set by
+	// the -debug flag.
+	Debug bool
+	// Version, Commit and BuildDate are printed by the -version flag. The flag
+	// is only recognized if Version is set.
+	Version   string
+	Commit    string
+	BuildDate string
+	// Writer and ErrWriter receive the output and the errors of Run. They
+	// default to os.Stdout and os.Stderr.
+	Writer    io.Writer
+	ErrWriter io.Writer
+	// EmptyMessage, if set, is written by Run when a command succeeds without
+	// returning data, e.g. "Done.". The JSON output is {"message": ...}.
+	EmptyMessage string
+	// Quiet suppresses the EmptyMessage. It is set by the -quiet flag.
+	Quiet bool
+	// NoColor removes the ANSI escape codes from the output written by Run. It
+	// is set by the -no-color flag.
+	NoColor bool
+	// Timeout bounds the execution of a command. If it expires, the command
+	// fails with an error with the code CodeTimeout. It is set by the
+	// -timeout flag, e.g. "-timeout 30s".
+	Timeout time.Duration
+	// NoTrailingNewline and NoTrailingNewlineErr omit the newline after the
+	// output and the errors written by Run.
+	NoTrailingNewline    bool
+	NoTrailingNewlineErr bool
+	// SecondaryFormatter and SecondaryWriter, if both set, additionally write
+	// the output of Run in a second format, e.g. JSON to a file.
+	SecondaryFormatter Formatter
+	SecondaryWriter    io.Writer
+	// ExitCode maps the error returned by a command to the exit code of Run.
+	// If nil, all errors exit with 1.
+	ExitCode func(err error) int
+	// ExpandAliases maps shortcuts to full invocations, e.g. "ls" to
+	// []string{"users", "list", "-limit", "10"}. If the first arg is an alias,
+	// it is replaced by the expansion before dispatching. An expansion may start
+	// with another alias, but each alias is expanded only once.
+	ExpandAliases map[string][]string
+	// PrefixMatching allows commands to be abbreviated by a unique prefix,
+	// e.g. "us" for "users". Ambiguous prefixes return an error with the code
+	// CodeAmbiguousCommand.
+	PrefixMatching bool
+	// StrictFlags rejects flags that are not in the KnownFlags of the command
+	// with an error with the code CodeUnknownFlag.
+	StrictFlags bool
+	// CacheResolution caches the lookup of commands by name, e.g. for servers
+	// running many commands with RunWithCommand. A cached command is only used
+	// while it is still at the same position of an unchanged number of
+	// commands. Call InvalidateCache after changing the Use of a command in
+	// place. The cache is created by Cli.
+	CacheResolution bool
+	// ArgName derives the args key of a model field without an arg tag from
+	// the field name, see CliRoot.InputFromModel. If nil, the lowercased name
+	// is used, e.g. "apikey" for APIKey. Set it to KebabCase for "api-key".
+	ArgName func(name string) string
+
+	elapsed time.Duration
+	// explicitFormatter is set if the formatter was chosen by an output flag.
+	explicitFormatter bool
+	// executed is the command executed by the run, see formatter.
+	executed    *Command[T]
+	globalFlags []GlobalFlag[T]
+	// cache is shared by the copies of the root made for each run.
+	cache *resolutionCache[T]
 }
 
 func (c *CliRoot[T]) Run() {
-	data, err := c.runCommand(c.Commands, os.Args[1:])
+	if code := c.RunArgs(os.Args[1:]); code != 0 {
+		os.Exit(code)
+	}
+}
+
+// RunArgs runs the command given by args, writes its output and returns the
+// exit code of the process.
+func (c *CliRoot[T]) RunArgs(args []string) int {
+	return c.invocation().runArgs(args)
+}
+
+// invocation returns a copy of the root for a single run. The global flags
+// and the command set the state of the copy, e.g. DryRun or the elapsed time,
+// so the state does not leak into later runs and concurrent runs do not share
+// it.
+func (c *CliRoot[T]) invocation() *CliRoot[T] {
+	run := *c
+	return &run
+}
+
+func (c *CliRoot[T]) runArgs(args []string) int {
+	stdout, stderr := c.writers()
+	data, err := c.runCommand(c.Commands, args)
 	if err != nil {
-		data := &DataError{
-			Message: err.Error(),
-		}
-		v, err := data.Display(c.Formatter)
+		data := AsData(err)
+		code := c.exitCode(err)
+		c.writeSecondary(data)
+		v, err := data.Display(wrapFor(c.formatter(), stderr))
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			c.writeLine(stdout, err.Error(), !c.NoTrailingNewline)
+			return code
+		}
+		c.writeLine(stderr, v, !c.NoTrailingNewlineErr)
+		return code
+	}
+	if data == nil && c.EmptyMessage != "" && !c.Quiet {
+		data = &DataMessage{Message: c.EmptyMessage}
+		if formatter := c.formatter(); isJSON(formatter) {
+			v, _ := formatter.Format(data)
+			data = &DataMessage{Message: v}
 		}
-		fmt.Fprintln(os.Stderr, v)
-		os.Exit(1)
 	}
 	if data != nil {
-		v1, _ := data.Display(c.Formatter)
-		fmt.Println(v1)
+		c.writeSecondary(data)
+		v1, _ := c.render(data, stdout)
+		c.writeLine(stdout, v1, !c.NoTrailingNewline)
+	}
+	return 0
+}
+
+func (c *CliRoot[T]) writeLine(w io.Writer, v string, newline bool) {
+	v = colorFor(w, v, c.NoColor)
+	if newline {
+		fmt.Fprintln(w, v)
+		return
 	}
+	fmt.Fprint(w, v)
 }
 
+func (c *CliRoot[T]) exitCode(err error) int {
+	if c.ExitCode == nil {
+		return 1
+	}
+	if code := c.ExitCode(err); code != 0 {
+		return code
+	}
+	return 1
+}
+
+func (c *CliRoot[T]) writers() (io.Writer, io.Writer) {
+	stdout, stderr := c.Writer, c.ErrWriter
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+	return stdout, stderr
+}
+
+// writeSecondary writes the data to the SecondaryWriter if configured.
+func (c *CliRoot[T]) writeSecondary(data Data) {
+	if c.SecondaryFormatter == nil || c.SecondaryWriter == nil {
+		return
+	}
+	v, err := data.Display(c.SecondaryFormatter)
+	if err != nil {
+		return
+	}
+	c.writeLine(c.SecondaryWriter, v, true)
+}
+
+// render displays the data written to w using the formatter of the run and
+// appends the execution time if ShowTiming is set.
+func (c *CliRoot[T]) render(data Data, w io.Writer) (string, error) {
+	formatter := wrapFor(c.formatter(), w)
+	v, err := data.Display(formatter)
+	if err != nil {
+		return "", err
+	}
+	if !c.ShowTiming {
+		return v, nil
+	}
+	if formatter.Type() == "ndjson" {
+		return fmt.Sprintf("%s\n{\"_elapsed_ms\":%d}", v, c.elapsed.Milliseconds()), nil
+	}
+	if formatter.Type() == "json" {
+		ms := c.elapsed.Milliseconds()
+		if v == "{}" {
+			return fmt.Sprintf(`{"_elapsed_ms":%d}`, ms), nil
+		}
+		if strings.HasPrefix(v, "{") && strings.HasSuffix(v, "}") {
+			return fmt.Sprintf(`%s,"_elapsed_ms":%d}`, strings.TrimSuffix(v, "}"), ms), nil
+		}
+		return v, nil
+	}
+	return fmt.Sprintf("%s\nelapsed: %s", v, c.elapsed), nil
+}
+
+// RunWithCommand runs the command given as a string and returns its data
+// without writing it. Flags such as -json only select the formatter of the
+// run, use RunWithCommandFormatter to display the data with it.
 func (c *CliRoot[T]) RunWithCommand(command string) (Data, error) {
+	data, _, err := c.RunWithCommandFormatter(command)
+	return data, err
+}
+
+// RunWithCommandFormatter works like RunWithCommand but also returns the
+// formatter chosen by the run, e.g. the JSON formatter for -json, so the caller
+// can display the data as Run would.
+func (c *CliRoot[T]) RunWithCommandFormatter(command string) (Data, Formatter, error) {
 	commandArgs := strings.Fields(command)
-	return c.runCommand(c.Commands, commandArgs)
+	run := c.invocation()
+	data, err := run.runCommand(c.Commands, commandArgs)
+	return data, run.formatter(), err
 }
 
 func (c *CliRoot[T]) runCommand(commands []*Command[T], args []string) (Data, error) {
-	filteredArgs := []string{}
-	for _, arg := range args {
-		if !strings.HasPrefix(arg, "-json") && !strings.HasPrefix(arg, "--json") {
-			filteredArgs = append(filteredArgs, arg)
-		} else {
-			if arg == "-json" || arg == "--json" {
-				c.Formatter = &JSONFormatter{}
+	args, err := expandResponseFiles(args)
+	if err != nil {
+		return nil, err
+	}
+	return c.dispatch(commands, c.expandAlias(args), nil)
+}
+
+// expandAlias replaces the first arg with its expansion in ExpandAliases.
+func (c *CliRoot[T]) expandAlias(args []string) []string {
+	seen := map[string]bool{}
+	for len(args) > 0 && !seen[args[0]] {
+		expansion, ok := c.ExpandAliases[args[0]]
+		if !ok {
+			break
+		}
+		seen[args[0]] = true
+		args = append(append([]string{}, expansion...), args[1:]...)
+	}
+	return args
+}
+
+// expandResponseFiles replaces every @path token with the whitespace-split
+// contents of the file. Response files can not reference other response files.
+func expandResponseFiles(args []string) ([]string, error) {
+	expanded := []string{}
+	for i, arg := range args {
+		if arg == "--" {
+			expanded = append(expanded, args[i:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '@' {
+			expanded = append(expanded, arg)
+			continue
+		}
+		content, err := os.ReadFile(arg[1:])
+		if err != nil {
+			return nil, fmt.Errorf("error reading response file: %w", err)
+		}
+		for _, token := range strings.Fields(string(content)) {
+			if len(token) > 1 && token[0] == '@' {
+				return nil, fmt.Errorf("nested response file %s in %s", token, arg[1:])
 			}
+			expanded = append(expanded, token)
 		}
 	}
+	return expanded, nil
+}
 
-	if len(filteredArgs) == 0 {
+func (c *CliRoot[T]) dispatch(commands []*Command[T], args []string, path []string) (Data, error) {
+	// the global flags before the command name apply to the whole run
+	args, err := c.takeGlobalFlags(args, nil, true, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
 		return c.Help(commands)
 	}
+
+	cmd, lookupErr := c.lookup(commands, args[0])
+	if cmd != nil && cmd.Commands != nil {
+		return c.dispatch(cmd.Commands, args[1:], append(path, cmd.Use))
+	}
+	// the global flags after the command name, unless the command declares a
+	// flag with the same name itself
+	var declared []string
+	if cmd != nil {
+		declared = cmd.knownFlags()
+	}
+	rest, err := c.takeGlobalFlags(args[1:], declared, false, true)
+	if err != nil {
+		return nil, err
+	}
+
 	// check if first argument is -help
-	if filteredArgs[0] == "-help" || filteredArgs[0] == "--help" {
+	if args[0] == "-help" || args[0] == "--help" {
 		return c.Help(commands)
 	}
+	// -version and -v are only recognized as the first token at the root, so
+	// commands can still use -v for other purposes, e.g. verbosity.
+	if path == nil && c.Version != "" && isVersionFlag(args[0]) {
+		return c.VersionData(), nil
+	}
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+	if cmd != nil {
+		return c.execute(cmd, append(path, cmd.Use), rest)
+	}
 
-	for _, cmd := range commands {
-		if cmd.Use == filteredArgs[0] {
-			if cmd.Commands == nil {
-				data, err := cmd.Run(cmd, filteredArgs[1:], c.Ctx)
-				return data, err
-			} else {
-				return c.runCommand(cmd.Commands, filteredArgs[1:])
+	if path == nil && args[0] == "help" {
+		return c.HelpFor(rest)
+	}
+
+	return nil, commandNotFound(args[0])
+}
+
+// takeGlobalFlags removes the global flags from args and returns the other
+// args. Flags named in declared are left in the args. If leading is set, it
+// stops at the first other arg, e.g. the command name. If handle is set, the
+// handlers of the flags are called.
+func (c *CliRoot[T]) takeGlobalFlags(args []string, declared []string, leading bool, handle bool) ([]string, error) {
+	rest := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		// everything after -- is positional and passed on unchanged
+		if arg == "--" {
+			return append(rest, args[i:]...), nil
+		}
+		flag, value, inline, ok := c.globalFlag(arg)
+		if !ok || slices.Contains(declared, flag.Name) {
+			if leading {
+				return append(rest, args[i:]...), nil
+			}
+			rest = append(rest, arg)
+			continue
+		}
+		if flag.TakesValue && !inline {
+			if i+1 >= len(args) {
+				if !handle {
+					break
+				}
+				return nil, &DataError{Message: "missing value for flag -" + flag.Name}
+			}
+			i++
+			value = args[i]
+		}
+		if handle {
+			if err := flag.Handler(c, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rest, nil
+}
+
+// Resolve returns the command the args would dispatch to together with its
+// remaining args, without running it. Global flags such as -json are ignored.
+// If the args end at a command with subcommands, that command is returned.
+// The built-in help and version handling is not considered.
+func (c *CliRoot[T]) Resolve(args []string) (*Command[T], []string, error) {
+	commands := c.Commands
+	var target *Command[T]
+	for {
+		args, _ = c.takeGlobalFlags(args, nil, true, false)
+		if len(args) == 0 {
+			if target == nil {
+				return nil, nil, fmt.Errorf("no command given")
 			}
+			return target, args, nil
+		}
+		next, err := c.lookup(commands, args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		if next == nil {
+			return nil, nil, commandNotFound(args[0])
+		}
+		target = next
+		if target.Commands == nil {
+			args, _ = c.takeGlobalFlags(args[1:], target.knownFlags(), false, false)
+			return target, args, nil
+		}
+		args = args[1:]
+		commands = target.Commands
+	}
+}
+
+// lookup returns the command with the given name, or with the name as unique
+// prefix if PrefixMatching is set. It returns nil if no command matches.
+func (c *CliRoot[T]) lookup(commands []*Command[T], name string) (*Command[T], error) {
+	if !c.CacheResolution || c.cache == nil {
+		return c.find(commands, name)
+	}
+	if cmd, ok := c.cache.get(commands, name); ok {
+		return cmd, nil
+	}
+	cmd, err := c.find(commands, name)
+	if err == nil && cmd != nil {
+		c.cache.put(commands, name, cmd)
+	}
+	return cmd, err
+}
+
+func (c *CliRoot[T]) find(commands []*Command[T], name string) (*Command[T], error) {
+	if !c.PrefixMatching {
+		for _, cmd := range commands {
+			if cmd.Use == name {
+				return cmd, nil
+			}
+		}
+		return nil, nil
+	}
+	matches, err := MatchPrefix(commands, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	return matches[0], nil
+}
+
+// MatchPrefix returns the commands whose Use starts with the prefix. A command
+// whose Use equals the prefix is returned as the only match. If several
+// commands match, they are returned together with an error with the code
+// CodeAmbiguousCommand.
+func MatchPrefix[T any](commands []*Command[T], prefix string) ([]*Command[T], error) {
+	matches := []*Command[T]{}
+	for _, cmd := range commands {
+		if cmd.Use == prefix {
+			return []*Command[T]{cmd}, nil
+		}
+		if strings.HasPrefix(cmd.Use, prefix) {
+			matches = append(matches, cmd)
 		}
 	}
+	if len(matches) > 1 {
+		names := make([]string, len(matches))
+		for i, cmd := range matches {
+			names[i] = cmd.Use
+		}
+		return matches, &DataError{
+			Message: fmt.Sprintf("command %s is ambiguous: %s", prefix, strings.Join(names, ", ")),
+			Code:    CodeAmbiguousCommand,
+			Details: map[string]string{"token": prefix, "candidates": strings.Join(names, ",")},
+		}
+	}
+	return matches, nil
+}
+
+func isVersionFlag(arg string) bool {
+	return arg == "-version" || arg == "--version" || arg == "-v"
+}
 
-	return nil, fmt.Errorf("command " + filteredArgs[0] + " not found")
+// VersionData returns the version information of the CLI.
+func (c *CliRoot[T]) VersionData() Data {
+	item := map[string]string{
+		"version": c.Version,
+	}
+	if c.Commit != "" {
+		item["commit"] = c.Commit
+	}
+	if c.BuildDate != "" {
+		item["build_date"] = c.BuildDate
+	}
+	return &DataDetails{
+		Title: "Version",
+		Item:  item,
+	}
+}
+
+// setFormatter sets the formatter chosen by an output flag.
+func (c *CliRoot[T]) setFormatter(f Formatter) {
+	c.Formatter = f
+	c.explicitFormatter = true
+}
+
+// formatter returns the formatter of the run: the one chosen by an output
+// flag, else the DefaultFormatter of the executed command, else the Formatter
+// of the root.
+func (c *CliRoot[T]) formatter() Formatter {
+	if !c.explicitFormatter && c.executed != nil && c.executed.DefaultFormatter != nil {
+		return c.executed.DefaultFormatter
+	}
+	return c.Formatter
+}
+
+func (c *CliRoot[T]) execute(cmd *Command[T], path []string, args []string) (Data, error) {
+	c.executed = cmd
+	if c.StrictFlags {
+		if err := checkFlags(cmd.knownFlags(), args); err != nil {
+			return nil, err
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if c.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), c.Timeout)
+	}
+	defer cancel()
+	// the command may still run after a timeout, so each execution gets its
+	// own copy of the command holding the context
+	run := *cmd
+	run.root = c
+	run.ctx = ctx
+
+	start := time.Now()
+	data, err := c.runWithTimeout(ctx, &run, args)
+	c.elapsed = time.Since(start)
+	if c.Logger != nil {
+		c.Logger(CommandLogEntry{
+			Path:     strings.Join(path, " "),
+			Args:     args,
+			Duration: c.elapsed,
+			Err:      err,
+		})
+	}
+	return data, err
+}
+
+// CodeTimeout is the Code of the error returned if a command exceeds the
+// Timeout.
+const CodeTimeout = "timeout"
+
+// runWithTimeout runs the command and returns an error if the Timeout expires
+// before the command finishes.
+func (c *CliRoot[T]) runWithTimeout(ctx context.Context, cmd *Command[T], args []string) (Data, error) {
+	if c.Timeout <= 0 {
+		return c.runWithRetry(cmd, args)
+	}
+
+	type result struct {
+		data Data
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		data, err := c.runWithRetry(cmd, args)
+		done <- result{data, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.data, r.err
+	case <-ctx.Done():
+		return nil, &DataError{
+			Message: fmt.Sprintf("command timed out after %s", c.Timeout),
+			Code:    CodeTimeout,
+			Err:     ctx.Err(),
+		}
+	}
+}
+
+func (c *CliRoot[T]) runWithRetry(cmd *Command[T], args []string) (Data, error) {
+	ctx, err := c.commandContext(cmd)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.call(cmd, args, ctx)
+	if cmd.Retry == nil {
+		return data, err
+	}
+	for attempt := 1; attempt < cmd.Retry.Attempts && err != nil; attempt++ {
+		if cmd.Retry.Retryable != nil && !cmd.Retry.Retryable(err) {
+			break
+		}
+		time.Sleep(cmd.Retry.Backoff)
+		data, err = c.call(cmd, args, ctx)
+	}
+	return data, err
+}
+
+// commandContext returns the context passed to Run, derived by the
+// ContextFunc of the command if set.
+func (c *CliRoot[T]) commandContext(cmd *Command[T]) (T, error) {
+	if cmd.ContextFunc == nil {
+		return c.Ctx, nil
+	}
+	ctx, err := cmd.ContextFunc(cmd, c.Ctx)
+	if err != nil {
+		if dataErr, ok := err.(*DataError); ok {
+			return ctx, dataErr
+		}
+		return ctx, &DataError{Message: err.Error(), Err: err}
+	}
+	return ctx, nil
+}
+
+// call runs the command and converts a panic into a DataError. The stack trace
+// is included if Debug is set.
+func (c *CliRoot[T]) call(cmd *Command[T], args []string, ctx T) (data Data, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			message := fmt.Sprintf("panic: %v", r)
+			if c.Debug {
+				message += "\n" + string(debug.Stack())
+			}
+			data, err = nil, &DataError{Message: message}
+		}
+	}()
+	return cmd.Run(cmd, args, ctx)
 }
 
 func (c *CliRoot[T]) Help(commands []*Command[T]) (Data, error) {
@@ -273,6 +1175,27 @@ func (c *CliRoot[T]) Help(commands []*Command[T]) (Data, error) {
 			Message: "No commands found",
 		}, nil
 	}
+	grouped := false
+	for _, cmd := range commands {
+		if cmd.Group != "" {
+			grouped = true
+		}
+	}
+	if grouped {
+		data := &DataHelp{
+			Title: "Available commands",
+			Items: []map[string]string{},
+		}
+		for _, cmd := range commands {
+			data.Items = append(data.Items, map[string]string{
+				"Use":   cmd.Use,
+				"Short": cmd.Short,
+				"Group": cmd.Group,
+			})
+		}
+		return data, nil
+	}
+
 	data := &DataList{
 		Title: "Available commands",
 		Items: []map[string]string{},
@@ -289,10 +1212,18 @@ func (c *CliRoot[T]) Help(commands []*Command[T]) (Data, error) {
 
 }
 
-func Cli[T any](ctx T, cmds []*Command[T]) *CliRoot[T] {
-	return &CliRoot[T]{
+// Option configures a CliRoot created with Cli.
+type Option[T any] func(c *CliRoot[T])
+
+func Cli[T any](ctx T, cmds []*Command[T], opts ...Option[T]) *CliRoot[T] {
+	c := &CliRoot[T]{
 		Ctx:       ctx,
 		Commands:  cmds,
 		Formatter: &TextFormatter{},
+		cache:     &resolutionCache[T]{},
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
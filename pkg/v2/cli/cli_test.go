@@ -1,7 +1,16 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 type Context struct{}
@@ -122,6 +131,30 @@ func TestFormatter(t *testing.T) {
 		}
 		f.Format(data)
 	})
+
+	t.Run("JSON unmarshalable", func(t *testing.T) {
+		type unmarshalable struct {
+			C chan int
+		}
+
+		f := &JSONFormatter{}
+		_, err := f.Format(&unmarshalable{})
+		if err == nil {
+			t.Fatalf("Expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "unmarshalable") {
+			t.Errorf("Expected error to mention the data type, got %s", err)
+		}
+
+		f = &JSONFormatter{Placeholder: "null"}
+		v, err := f.Format(&unmarshalable{})
+		if err != nil {
+			t.Errorf("Expected nil, got %s", err)
+		}
+		if v != "null" {
+			t.Errorf("Expected placeholder, got %s", v)
+		}
+	})
 }
 
 func TestData(t *testing.T) {
@@ -144,9 +177,11 @@ func TestData(t *testing.T) {
 	t.Run("DataDetails", func(t *testing.T) {
 		data := &DataDetails{
 			Title: "Details",
-			Item:  map[string]string{"key": "value"},
+			Item:  map[string]string{"key": "value", "id": "1"},
+		}
+		if v, _ := data.Display(&TextFormatter{}); v != "Details\nid: 1\nkey: value" {
+			t.Errorf("Expected sorted text output, got %q", v)
 		}
-		data.Display(&TextFormatter{})
 		data.Display(&JSONFormatter{})
 	})
 
@@ -161,3 +196,1176 @@ func TestData(t *testing.T) {
 		data.Display(&JSONFormatter{})
 	})
 }
+
+func TestLogger(t *testing.T) {
+	ctx := &Context{}
+	cmds := []*Command[*Context]{
+		{
+			Use: "users",
+			Commands: []*Command[*Context]{
+				{
+					Use: "create",
+					Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+						return nil, errors.New("failed")
+					},
+				},
+			},
+		},
+	}
+
+	var entries []CommandLogEntry
+	c := Cli[*Context](ctx, cmds)
+	c.Logger = func(entry CommandLogEntry) {
+		entries = append(entries, entry)
+	}
+	c.RunWithCommand("users create -name test")
+
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(entries))
+	}
+	if entries[0].Path != "users create" {
+		t.Errorf("Expected path users create, got %s", entries[0].Path)
+	}
+	if len(entries[0].Args) != 2 {
+		t.Errorf("Expected 2 args, got %d", len(entries[0].Args))
+	}
+	if entries[0].Err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
+
+func TestDataListColumns(t *testing.T) {
+	data := &DataList{
+		Title: "Users",
+		Items: []map[string]string{
+			{"id": "1", "email": "a@example.com", "name": "A"},
+			{"id": "2", "name": "B"},
+		},
+		Columns: []string{"name", "email"},
+	}
+
+	v, err := data.Display(&TextFormatter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "Users\nname: A\nemail: a@example.com\nname: B\nemail: "
+	if v != expected {
+		t.Errorf("Expected %q, got %q", expected, v)
+	}
+
+	v, err = data.Display(&JSONFormatter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(v, `"id":"1"`) {
+		t.Errorf("Expected JSON to contain all keys, got %s", v)
+	}
+}
+
+func TestShowTiming(t *testing.T) {
+	ctx := &Context{}
+	cmds := []*Command[*Context]{
+		{
+			Use: "details",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return &DataDetails{
+					Title: "Details",
+					Item:  map[string]string{"key": "value"},
+				}, nil
+			},
+		},
+	}
+
+	run := func(c *CliRoot[*Context], args ...string) string {
+		out := &bytes.Buffer{}
+		c.Writer = out
+		c.RunArgs(args)
+		return out.String()
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		c := Cli[*Context](ctx, cmds)
+		if v := run(c, "details"); strings.Contains(v, "elapsed") {
+			t.Errorf("Expected no timing, got %s", v)
+		}
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		c := Cli[*Context](ctx, cmds)
+		c.ShowTiming = true
+		if v := run(c, "details"); !strings.Contains(v, "\nelapsed: ") {
+			t.Errorf("Expected timing footer, got %s", v)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		c := Cli[*Context](ctx, cmds)
+		c.ShowTiming = true
+		if v := run(c, "details", "-json"); !strings.Contains(v, `"_elapsed_ms":`) {
+			t.Errorf("Expected _elapsed_ms field, got %s", v)
+		}
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	for _, command := range []string{"--dry-run users delete -id 1", "users -dry-run delete -id 1", "users delete -id 1 --dry-run"} {
+		t.Run(command, func(t *testing.T) {
+			ctx := &Context{}
+			var dryRun bool
+			var received []string
+			cmds := []*Command[*Context]{
+				{
+					Use: "users",
+					Commands: []*Command[*Context]{
+						{
+							Use: "delete",
+							Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+								dryRun = cmd.Root().DryRun
+								received = args
+								return nil, nil
+							},
+						},
+					},
+				},
+			}
+
+			c := Cli[*Context](ctx, cmds)
+			_, err := c.RunWithCommand(command)
+			if err != nil {
+				t.Fatalf("Expected nil, got %s", err)
+			}
+			if !dryRun {
+				t.Errorf("Expected DryRun to be true")
+			}
+			if len(received) != 2 {
+				t.Errorf("Expected dry-run flag to be removed from args, got %v", received)
+			}
+		})
+	}
+}
+
+func TestInvocationState(t *testing.T) {
+	var dryRun, debug bool
+	cmds := []*Command[*Context]{
+		{
+			Use: "delete",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				dryRun, debug = cmd.Root().DryRun, cmd.Root().Debug
+				return nil, nil
+			},
+		},
+	}
+	c := Cli[*Context](&Context{}, cmds)
+	c.Writer = &bytes.Buffer{}
+
+	c.RunArgs([]string{"delete", "-dry-run", "-debug"})
+	if !dryRun || !debug {
+		t.Errorf("Expected DryRun and Debug to be set by the flags")
+	}
+	c.RunArgs([]string{"delete"})
+	if dryRun || debug {
+		t.Errorf("Expected DryRun and Debug not to leak into the next run")
+	}
+	if c.DryRun || c.Debug || cmds[0].Root() != nil {
+		t.Errorf("Expected the root and the command to stay unchanged")
+	}
+
+	c.DryRun = true
+	c.RunArgs([]string{"delete"})
+	if !dryRun {
+		t.Errorf("Expected DryRun configured on the root to apply to every run")
+	}
+}
+
+func TestRunWithCommandFormatter(t *testing.T) {
+	cmds := []*Command[*Context]{
+		{
+			Use: "user",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return &DataDetails{Item: map[string]string{"name": "Max"}}, nil
+			},
+		},
+	}
+	c := Cli[*Context](&Context{}, cmds)
+
+	data, formatter, err := c.RunWithCommandFormatter("user -json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v, _ := data.Display(formatter); v != `{"title":"","item":{"name":"Max"}}` {
+		t.Errorf("Expected json output, got %q", v)
+	}
+	if _, ok := c.Formatter.(*TextFormatter); !ok {
+		t.Errorf("Expected the formatter of the root to stay unchanged, got %s", c.Formatter.Type())
+	}
+
+	_, formatter, _ = c.RunWithCommandFormatter("user")
+	if formatter != c.Formatter {
+		t.Errorf("Expected the formatter of the root without flags, got %s", formatter.Type())
+	}
+}
+
+func TestConcurrentRuns(t *testing.T) {
+	cmds := []*Command[*Context]{
+		{
+			Use: "echo",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return &DataMessage{Message: fmt.Sprintf("%s %v", strings.Join(args, " "), cmd.Root().DryRun)}, nil
+			},
+		},
+	}
+	c := Cli[*Context](&Context{}, cmds)
+	c.ShowTiming = true
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			command := fmt.Sprintf("echo %d", i)
+			expected := fmt.Sprintf("%d false", i)
+			if i%2 == 0 {
+				command += " -dry-run -json"
+				expected = fmt.Sprintf("%d true", i)
+			}
+			data, err := c.RunWithCommand(command)
+			if err != nil {
+				t.Errorf("Expected nil, got %s", err)
+				return
+			}
+			if v := data.(*DataMessage).Message; v != expected {
+				t.Errorf("Expected %q, got %q", expected, v)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("Succeeds after failures", func(t *testing.T) {
+		calls := 0
+		cmds := []*Command[*Context]{
+			{
+				Use:   "flaky",
+				Retry: &RetryPolicy{Attempts: 3, Backoff: time.Millisecond},
+				Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+					calls++
+					if calls < 3 {
+						return nil, errors.New("temporary")
+					}
+					return &DataMessage{Message: "ok"}, nil
+				},
+			},
+		}
+
+		c := Cli[*Context](&Context{}, cmds)
+		data, err := c.RunWithCommand("flaky")
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+		if calls != 3 {
+			t.Errorf("Expected 3 calls, got %d", calls)
+		}
+		if data.(*DataMessage).Message != "ok" {
+			t.Errorf("Expected ok, got %v", data)
+		}
+	})
+
+	t.Run("Non-retryable error", func(t *testing.T) {
+		calls := 0
+		errFatal := errors.New("fatal")
+		cmds := []*Command[*Context]{
+			{
+				Use: "fatal",
+				Retry: &RetryPolicy{
+					Attempts:  3,
+					Retryable: func(err error) bool { return !errors.Is(err, errFatal) },
+				},
+				Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+					calls++
+					return nil, errFatal
+				},
+			},
+		}
+
+		c := Cli[*Context](&Context{}, cmds)
+		_, err := c.RunWithCommand("fatal")
+		if !errors.Is(err, errFatal) {
+			t.Errorf("Expected fatal error, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("Expected 1 call, got %d", calls)
+		}
+	})
+}
+
+func TestVersionFlag(t *testing.T) {
+	var verbose bool
+	cmds := []*Command[*Context]{
+		{
+			Use: "list",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				verbose = len(args) > 0 && args[0] == "-v"
+				return nil, nil
+			},
+		},
+	}
+
+	for _, command := range []string{"--version", "-v", "-version -json"} {
+		t.Run(command, func(t *testing.T) {
+			c := Cli[*Context](&Context{}, cmds)
+			c.Version = "1.2.3"
+			c.Commit = "abc"
+			data, err := c.RunWithCommand(command)
+			if err != nil {
+				t.Fatalf("Expected nil, got %s", err)
+			}
+			details, ok := data.(*DataDetails)
+			if !ok {
+				t.Fatalf("Expected DataDetails, got %T", data)
+			}
+			if details.Item["version"] != "1.2.3" || details.Item["commit"] != "abc" {
+				t.Errorf("Unexpected version data: %v", details.Item)
+			}
+		})
+	}
+
+	t.Run("Command flag", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		c.Version = "1.2.3"
+		c.RunWithCommand("list -v")
+		if !verbose {
+			t.Errorf("Expected -v to be passed to the command")
+		}
+	})
+
+	t.Run("No version", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		_, err := c.RunWithCommand("--version")
+		if err == nil {
+			t.Errorf("Expected command not found error")
+		}
+	})
+}
+
+func TestDataListPagination(t *testing.T) {
+	t.Run("Without metadata", func(t *testing.T) {
+		data := &DataList{
+			Title: "Users",
+			Items: []map[string]string{{"id": "1"}},
+		}
+		v, _ := data.Display(&JSONFormatter{})
+		if strings.Contains(v, "total") || strings.Contains(v, "page") {
+			t.Errorf("Expected no pagination metadata, got %s", v)
+		}
+		v, _ = data.Display(&TextFormatter{})
+		if v != "Users\nid: 1" {
+			t.Errorf("Expected no footer, got %q", v)
+		}
+	})
+
+	t.Run("With metadata", func(t *testing.T) {
+		data := &DataList{
+			Title:    "Users",
+			Items:    []map[string]string{{"id": "1"}},
+			Total:    120,
+			Page:     2,
+			PageSize: 20,
+		}
+		v, _ := data.Display(&JSONFormatter{})
+		if !strings.Contains(v, `"total":120,"page":2,"page_size":20`) {
+			t.Errorf("Expected pagination metadata, got %s", v)
+		}
+		v, _ = data.Display(&TextFormatter{})
+		if !strings.HasSuffix(v, "\ntotal: 120, page: 2, page size: 20") {
+			t.Errorf("Expected footer, got %q", v)
+		}
+		v, _ = data.Display(&TableFormatter{})
+		if !strings.HasSuffix(v, "\ntotal: 120, page: 2, page size: 20") {
+			t.Errorf("Expected footer, got %q", v)
+		}
+	})
+}
+
+func TestRecoverPanic(t *testing.T) {
+	cmds := []*Command[*Context]{
+		{
+			Use: "panic",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				panic("something went wrong")
+			},
+		},
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		data, err := c.RunWithCommand("panic")
+		if data != nil {
+			t.Errorf("Expected nil data, got %v", data)
+		}
+		var dataErr *DataError
+		if !errors.As(err, &dataErr) {
+			t.Fatalf("Expected DataError, got %v", err)
+		}
+		if dataErr.Message != "panic: something went wrong" {
+			t.Errorf("Unexpected message: %s", dataErr.Message)
+		}
+	})
+
+	t.Run("Debug", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		_, err := c.RunWithCommand("panic -debug")
+		if err == nil || !strings.Contains(err.Error(), "goroutine") {
+			t.Errorf("Expected stack trace, got %v", err)
+		}
+	})
+}
+
+func TestSecondaryOutput(t *testing.T) {
+	cmds := []*Command[*Context]{
+		{
+			Use: "list",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return &DataList{
+					Title: "Users",
+					Items: []map[string]string{{"id": "1"}},
+				}, nil
+			},
+		},
+	}
+
+	var stdout, artifact bytes.Buffer
+	c := Cli[*Context](&Context{}, cmds)
+	c.Formatter = &TableFormatter{}
+	c.Writer = &stdout
+	c.SecondaryFormatter = &JSONFormatter{}
+	c.SecondaryWriter = &artifact
+
+	if code := c.RunArgs([]string{"list"}); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	if stdout.String() != "Users\nID\n--\n1\n" {
+		t.Errorf("Unexpected stdout: %q", stdout.String())
+	}
+	if artifact.String() != `{"title":"Users","items":[{"id":"1"}]}`+"\n" {
+		t.Errorf("Unexpected artifact: %q", artifact.String())
+	}
+}
+
+func TestDataErrorUnwrap(t *testing.T) {
+	data := &DataError{
+		Message: "config not found",
+		Err:     fmt.Errorf("reading config: %w", os.ErrNotExist),
+	}
+	if !errors.Is(data, os.ErrNotExist) {
+		t.Errorf("Expected errors.Is to find os.ErrNotExist")
+	}
+	v, _ := data.Display(&JSONFormatter{})
+	if v != `{"error":"config not found"}` {
+		t.Errorf("Unexpected JSON output: %s", v)
+	}
+
+	cmds := []*Command[*Context]{
+		{
+			Use: "read",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return nil, fmt.Errorf("reading config: %w", os.ErrNotExist)
+			},
+		},
+	}
+	c := Cli[*Context](&Context{}, cmds)
+	c.ErrWriter = &bytes.Buffer{}
+	c.ExitCode = func(err error) int {
+		if errors.Is(err, os.ErrNotExist) {
+			return 2
+		}
+		return 1
+	}
+	if code := c.RunArgs([]string{"read"}); code != 2 {
+		t.Errorf("Expected exit code 2, got %d", code)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	called := false
+	run := func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+		called = true
+		return nil, nil
+	}
+	cmds := []*Command[*Context]{
+		{Use: "version", Run: run},
+		{
+			Use: "users",
+			Commands: []*Command[*Context]{
+				{Use: "list", Run: run},
+				{Use: "create", Run: run},
+			},
+		},
+	}
+	c := Cli[*Context](&Context{}, cmds)
+
+	t.Run("Nested", func(t *testing.T) {
+		cmd, args, err := c.Resolve([]string{"users", "-json", "create", "-email", "max@example.com"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cmd != cmds[1].Commands[1] {
+			t.Errorf("Expected users create, got %s", cmd.Use)
+		}
+		if len(args) != 2 || args[0] != "-email" {
+			t.Errorf("Unexpected remaining args: %v", args)
+		}
+		if called {
+			t.Errorf("Expected Run not to be called")
+		}
+	})
+
+	t.Run("Group", func(t *testing.T) {
+		cmd, _, err := c.Resolve([]string{"users"})
+		if err != nil || cmd != cmds[1] {
+			t.Errorf("Expected users, got %v, %v", cmd, err)
+		}
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, _, err := c.Resolve([]string{"users", "delete"})
+		if err == nil || err.Error() != "command delete not found" {
+			t.Errorf("Expected not found error, got %v", err)
+		}
+	})
+}
+
+func TestArgsSeparator(t *testing.T) {
+	var received []string
+	cmds := []*Command[*Context]{
+		{
+			Use: "cat",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				received = args
+				return nil, nil
+			},
+		},
+	}
+
+	c := Cli[*Context](&Context{}, cmds)
+	c.RunWithCommand("cat -- -weird.txt -json")
+	if c.Formatter.Type() != "text" {
+		t.Errorf("Expected -json after -- not to change the formatter")
+	}
+	if len(received) != 3 || received[1] != "-weird.txt" || received[2] != "-json" {
+		t.Errorf("Expected positional args to be passed unchanged, got %v", received)
+	}
+
+	cmd, args, _ := c.Resolve([]string{"cat", "--", "-json"})
+	if cmd != cmds[0] || len(args) != 2 {
+		t.Errorf("Expected Resolve to keep args after --, got %v", args)
+	}
+}
+
+func TestDataListGroupBy(t *testing.T) {
+	data := &DataList{
+		Title: "Users",
+		Items: []map[string]string{
+			{"name": "A", "team": "core"},
+			{"name": "B", "team": "web"},
+			{"name": "C"},
+			{"name": "D", "team": "core"},
+		},
+		Columns: []string{"name"},
+		GroupBy: "team",
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		v, err := data.Display(&TextFormatter{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := "Users\n\ncore\nname: A\nname: D\n\nweb\nname: B\n\nungrouped\nname: C"
+		if v != expected {
+			t.Errorf("Expected %q, got %q", expected, v)
+		}
+	})
+
+	t.Run("Table", func(t *testing.T) {
+		v, _ := data.Display(&TableFormatter{})
+		expected := "Users\n\ncore\nName\n----\nA\nD\n\nweb\nName\n----\nB\n\nungrouped\nName\n----\nC"
+		if v != expected {
+			t.Errorf("Expected %q, got %q", expected, v)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		v, _ := data.Display(&JSONFormatter{})
+		expected := `{"title":"Users","groups":{"core":[{"name":"A","team":"core"},{"name":"D","team":"core"}],"ungrouped":[{"name":"C"}],"web":[{"name":"B","team":"web"}]}}`
+		if v != expected {
+			t.Errorf("Expected %s, got %s", expected, v)
+		}
+	})
+}
+
+func TestCommandNotFound(t *testing.T) {
+	cmds := []*Command[*Context]{{Use: "version"}}
+
+	t.Run("JSON", func(t *testing.T) {
+		var stderr bytes.Buffer
+		c := Cli[*Context](&Context{}, cmds)
+		c.ErrWriter = &stderr
+		if code := c.RunArgs([]string{"bogus", "-json"}); code != 1 {
+			t.Errorf("Expected exit code 1, got %d", code)
+		}
+		expected := `{"error":"command bogus not found","code":"command_not_found","details":{"token":"bogus"}}` + "\n"
+		if stderr.String() != expected {
+			t.Errorf("Expected %s, got %s", expected, stderr.String())
+		}
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		var stderr bytes.Buffer
+		c := Cli[*Context](&Context{}, cmds)
+		c.ErrWriter = &stderr
+		c.RunArgs([]string{"bogus"})
+		if stderr.String() != "command bogus not found\n" {
+			t.Errorf("Unexpected text output: %q", stderr.String())
+		}
+	})
+}
+
+func TestResponseFile(t *testing.T) {
+	var received []string
+	var root *CliRoot[*Context]
+	cmds := []*Command[*Context]{
+		{
+			Use: "import",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				received = args
+				root = cmd.Root()
+				return nil, nil
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "args")
+	if err := os.WriteFile(path, []byte("-file users.csv\n-limit 10\n-dry-run\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Expand", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		if _, err := c.RunWithCommand("import @" + path + " -json"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(received, " ") != "-file users.csv -limit 10" {
+			t.Errorf("Unexpected args: %v", received)
+		}
+		if !root.DryRun || root.Formatter.Type() != "json" {
+			t.Errorf("Expected global flags from the response file to be applied")
+		}
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		nested := filepath.Join(dir, "nested")
+		if err := os.WriteFile(nested, []byte("-limit 5 @"+path), 0600); err != nil {
+			t.Fatal(err)
+		}
+		c := Cli[*Context](&Context{}, cmds)
+		if _, err := c.RunWithCommand("import @" + nested); err == nil {
+			t.Errorf("Expected error for nested response file")
+		}
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		if _, err := c.RunWithCommand("import @" + filepath.Join(dir, "missing")); err == nil {
+			t.Errorf("Expected error for missing response file")
+		}
+	})
+}
+
+func TestDefaultFormatter(t *testing.T) {
+	cmds := []*Command[*Context]{
+		{
+			Use:              "export",
+			DefaultFormatter: &JSONFormatter{},
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return &DataDetails{Title: "exported"}, nil
+			},
+		},
+		{
+			Use: "show",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return &DataDetails{Title: "shown"}, nil
+			},
+		},
+	}
+
+	run := func(args ...string) string {
+		var out bytes.Buffer
+		c := Cli[*Context](&Context{}, cmds)
+		c.Writer = &out
+		c.RunArgs(args)
+		return strings.TrimSpace(out.String())
+	}
+
+	if v := run("export"); v != `{"title":"exported","item":null}` {
+		t.Errorf("Expected JSON output by default, got %q", v)
+	}
+	if v := run("export", "-text"); v != "exported" {
+		t.Errorf("Expected -text to win, got %q", v)
+	}
+	if v := run("-csv", "export"); v == `{"title":"exported","item":null}` {
+		t.Errorf("Expected -csv to win, got %q", v)
+	}
+	if v := run("show"); v != "shown" {
+		t.Errorf("Expected text output for commands without default, got %q", v)
+	}
+	if v := run("show", "-json"); v != `{"title":"shown","item":null}` {
+		t.Errorf("Expected JSON output with -json, got %q", v)
+	}
+
+	t.Run("Sequence", func(t *testing.T) {
+		var out bytes.Buffer
+		c := Cli[*Context](&Context{}, cmds)
+		c.Writer = &out
+		c.RunArgs([]string{"export"})
+		out.Reset()
+		c.RunArgs([]string{"show"})
+		if v := strings.TrimSpace(out.String()); v != "shown" {
+			t.Errorf("Expected the default formatter not to apply to the next command, got %q", v)
+		}
+		if c.Formatter.Type() != "text" {
+			t.Errorf("Expected the root formatter to stay text, got %s", c.Formatter.Type())
+		}
+	})
+}
+
+func TestDataListShowIndex(t *testing.T) {
+	items := []map[string]string{
+		{"name": "Max"},
+		{"name": "Erika"},
+	}
+
+	t.Run("Without offset", func(t *testing.T) {
+		data := &DataList{Items: items, ShowIndex: true}
+		v, _ := data.Display(&CSVFormatter{})
+		if v != "#,name\n1,Max\n2,Erika" {
+			t.Errorf("Unexpected CSV output: %q", v)
+		}
+
+		v, _ = data.Display(&TableFormatter{})
+		if v != "#  Name\n-  -----\n1  Max\n2  Erika" {
+			t.Errorf("Unexpected table output:\n%s", v)
+		}
+
+		v, _ = data.Display(&JSONFormatter{})
+		if v != `{"title":"","items":[{"name":"Max"},{"name":"Erika"}]}` {
+			t.Errorf("Expected no index in JSON output, got %s", v)
+		}
+		if _, ok := items[0][IndexColumn]; ok {
+			t.Errorf("Expected items not to be modified")
+		}
+	})
+
+	t.Run("With offset", func(t *testing.T) {
+		data := &DataList{Items: items, ShowIndex: true, Page: 3, PageSize: 2}
+		v, _ := data.Display(&TextFormatter{})
+		if v != "\n#: 5\nname: Max\n#: 6\nname: Erika\npage: 3, page size: 2" {
+			t.Errorf("Unexpected text output: %q", v)
+		}
+	})
+}
+
+func TestExpandAliases(t *testing.T) {
+	var received []string
+	cmds := []*Command[*Context]{
+		{
+			Use: "users",
+			Commands: []*Command[*Context]{
+				{
+					Use: "list",
+					Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+						received = args
+						return nil, nil
+					},
+				},
+			},
+		},
+	}
+
+	c := Cli[*Context](&Context{}, cmds)
+	c.ExpandAliases = map[string][]string{
+		"ls":   {"users", "list", "-limit", "10"},
+		"la":   {"ls", "-all"},
+		"loop": {"loop", "users"},
+	}
+
+	t.Run("Expand", func(t *testing.T) {
+		if _, err := c.RunWithCommand("ls -name Max"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(received, " ") != "-limit 10 -name Max" {
+			t.Errorf("Unexpected args: %v", received)
+		}
+	})
+
+	t.Run("Chained", func(t *testing.T) {
+		if _, err := c.RunWithCommand("la"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(received, " ") != "-limit 10 -all" {
+			t.Errorf("Unexpected args: %v", received)
+		}
+	})
+
+	t.Run("Recursive", func(t *testing.T) {
+		_, err := c.RunWithCommand("loop")
+		var dataErr *DataError
+		if !errors.As(err, &dataErr) || dataErr.Code != CodeCommandNotFound {
+			t.Errorf("Expected command not found, got %v", err)
+		}
+	})
+}
+
+func TestPrefixMatching(t *testing.T) {
+	var called string
+	leaf := func(name string) *Command[*Context] {
+		return &Command[*Context]{
+			Use: name,
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				called = name
+				return nil, nil
+			},
+		}
+	}
+	cmds := []*Command[*Context]{leaf("users"), leaf("usage"), leaf("status"), leaf("stat")}
+
+	t.Run("MatchPrefix", func(t *testing.T) {
+		if m, err := MatchPrefix(cmds, "use"); err != nil || len(m) != 1 || m[0].Use != "users" {
+			t.Errorf("Expected unique match users, got %v, %v", m, err)
+		}
+		if m, err := MatchPrefix(cmds, "us"); err == nil || len(m) != 2 {
+			t.Errorf("Expected ambiguous match, got %v, %v", m, err)
+		}
+		if m, err := MatchPrefix(cmds, "stat"); err != nil || len(m) != 1 || m[0].Use != "stat" {
+			t.Errorf("Expected exact match to win, got %v, %v", m, err)
+		}
+		if m, err := MatchPrefix(cmds, "x"); err != nil || len(m) != 0 {
+			t.Errorf("Expected no match, got %v, %v", m, err)
+		}
+	})
+
+	t.Run("Dispatch", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		c.PrefixMatching = true
+
+		if _, err := c.RunWithCommand("use"); err != nil || called != "users" {
+			t.Errorf("Expected users to be called, got %s, %v", called, err)
+		}
+
+		_, err := c.RunWithCommand("us")
+		var dataErr *DataError
+		if !errors.As(err, &dataErr) || dataErr.Code != CodeAmbiguousCommand || dataErr.Details["candidates"] != "users,usage" {
+			t.Errorf("Expected ambiguous command error, got %v", err)
+		}
+
+		_, err = c.RunWithCommand("x")
+		if !errors.As(err, &dataErr) || dataErr.Code != CodeCommandNotFound {
+			t.Errorf("Expected command not found, got %v", err)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		if _, err := c.RunWithCommand("use"); err == nil {
+			t.Errorf("Expected prefixes to be rejected by default")
+		}
+	})
+}
+
+func TestNoTrailingNewline(t *testing.T) {
+	cmds := []*Command[*Context]{
+		{
+			Use: "hello",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return &DataMessage{Message: "hello"}, nil
+			},
+		},
+	}
+
+	var stdout, stderr bytes.Buffer
+	c := Cli[*Context](&Context{}, cmds)
+	c.Writer = &stdout
+	c.ErrWriter = &stderr
+
+	c.RunArgs([]string{"hello"})
+	c.RunArgs([]string{"missing"})
+	if stdout.String() != "hello\n" || stderr.String() != "command missing not found\n" {
+		t.Errorf("Expected trailing newlines by default, got %q and %q", stdout.String(), stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	c.NoTrailingNewline = true
+	c.NoTrailingNewlineErr = true
+	c.RunArgs([]string{"hello"})
+	c.RunArgs([]string{"missing"})
+	if stdout.String() != "hello" || stderr.String() != "command missing not found" {
+		t.Errorf("Expected no trailing newlines, got %q and %q", stdout.String(), stderr.String())
+	}
+}
+
+func TestStrictFlags(t *testing.T) {
+	cmds := []*Command[*Context]{
+		{
+			Use:        "list",
+			KnownFlags: []string{"limit", "name"},
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return nil, nil
+			},
+		},
+		{
+			Use:   "show",
+			Flags: []FlagSpec{{Name: "id", Short: "i"}},
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return nil, nil
+			},
+		},
+	}
+
+	t.Run("Strict", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		c.StrictFlags = true
+
+		if _, err := c.RunWithCommand("list -limit 10 --name=max -json -- -other"); err != nil {
+			t.Errorf("Expected known flags to be accepted, got %v", err)
+		}
+		if _, err := c.RunWithCommand("show -id 1 -i 2"); err != nil {
+			t.Errorf("Expected flags of FlagSpecs to be accepted, got %v", err)
+		}
+		if _, err := c.RunWithCommand("list -offset -5"); err == nil {
+			t.Errorf("Expected error for unknown flag")
+		}
+
+		_, err := c.RunWithCommand("list -limit 10 -lmit 5")
+		var dataErr *DataError
+		if !errors.As(err, &dataErr) || dataErr.Code != CodeUnknownFlag {
+			t.Fatalf("Expected unknown flag error, got %v", err)
+		}
+		if dataErr.Message != "unknown flag -lmit, valid flags: -limit, -name" || dataErr.Details["flag"] != "lmit" {
+			t.Errorf("Unexpected error: %s %v", dataErr.Message, dataErr.Details)
+		}
+	})
+
+	t.Run("Tolerant", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		if _, err := c.RunWithCommand("list -lmit 5"); err != nil {
+			t.Errorf("Expected unknown flags to be ignored, got %v", err)
+		}
+	})
+}
+
+func TestEmptyMessage(t *testing.T) {
+	cmds := []*Command[*Context]{
+		{
+			Use: "sync",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return nil, nil
+			},
+		},
+	}
+
+	run := func(message string, args ...string) string {
+		var out bytes.Buffer
+		c := Cli[*Context](&Context{}, cmds)
+		c.Writer = &out
+		c.EmptyMessage = message
+		c.RunArgs(args)
+		return out.String()
+	}
+
+	if v := run("", "sync"); v != "" {
+		t.Errorf("Expected no output without EmptyMessage, got %q", v)
+	}
+	if v := run("", "sync", "-json"); v != "" {
+		t.Errorf("Expected no JSON output without EmptyMessage, got %q", v)
+	}
+	if v := run("Done.", "sync"); v != "Done.\n" {
+		t.Errorf("Expected EmptyMessage, got %q", v)
+	}
+	if v := run("Done.", "sync", "-json"); v != "{\"message\":\"Done.\"}\n" {
+		t.Errorf("Expected EmptyMessage as JSON, got %q", v)
+	}
+	if v := run("Done.", "sync", "-quiet"); v != "" {
+		t.Errorf("Expected no output in quiet mode, got %q", v)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	cmds := []*Command[*Context]{
+		{
+			Use: "sleep",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				d, _ := time.ParseDuration(args[0])
+				select {
+				case <-time.After(d):
+					return &DataMessage{Message: "done"}, nil
+				case <-cmd.Context().Done():
+					return nil, cmd.Context().Err()
+				}
+			},
+		},
+	}
+
+	t.Run("Exceeded", func(t *testing.T) {
+		var stderr bytes.Buffer
+		c := Cli[*Context](&Context{}, cmds)
+		c.ErrWriter = &stderr
+		if code := c.RunArgs([]string{"sleep", "1s", "--timeout", "10ms"}); code == 0 {
+			t.Errorf("Expected non-zero exit code")
+		}
+		if stderr.String() != "command timed out after 10ms\n" {
+			t.Errorf("Unexpected error output: %q", stderr.String())
+		}
+
+		_, err := c.RunWithCommand("sleep 1s -timeout=10ms")
+		var dataErr *DataError
+		if !errors.As(err, &dataErr) || dataErr.Code != CodeTimeout || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected timeout error, got %v", err)
+		}
+	})
+
+	t.Run("Within", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		data, err := c.RunWithCommand("sleep 1ms -timeout 1s")
+		if err != nil || data == nil {
+			t.Errorf("Expected command to finish, got %v", err)
+		}
+		if cmd, args, _ := c.Resolve([]string{"--timeout", "1s", "sleep", "1ms"}); cmd != cmds[0] || len(args) != 1 {
+			t.Errorf("Expected Resolve to skip the timeout flag, got %v", args)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		_, err := c.RunWithCommand("sleep 1ms -timeout soon")
+		if err == nil || err.Error() != `invalid timeout "soon", expected a duration such as 30s` {
+			t.Errorf("Expected invalid timeout error, got %v", err)
+		}
+		if _, err := c.RunWithCommand("sleep 1ms -timeout"); err == nil {
+			t.Errorf("Expected error for missing timeout value")
+		}
+	})
+
+	t.Run("PerRun", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		if _, err := c.RunWithCommand("sleep 50ms -timeout 10ms"); err == nil {
+			t.Errorf("Expected timeout error")
+		}
+		if _, err := c.RunWithCommand("sleep 50ms"); err != nil {
+			t.Errorf("Expected the timeout not to apply to the next run, got %v", err)
+		}
+	})
+
+	t.Run("Declared", func(t *testing.T) {
+		type syncInput struct {
+			Timeout time.Duration `validate:"required"`
+		}
+		var input syncInput
+		var root *CliRoot[*Context]
+		cmds := []*Command[*Context]{
+			{
+				Use:        "sync",
+				KnownFlags: []string{"timeout"},
+				Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+					root = cmd.Root()
+					return nil, InputFromModel(&input, ParseArgs(args))
+				},
+			},
+		}
+		c := Cli[*Context](&Context{}, cmds)
+		if _, err := c.RunWithCommand("sync -timeout 30s"); err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		if input.Timeout != 30*time.Second || root.Timeout != 0 {
+			t.Errorf("Expected the command to receive -timeout, got %s and root timeout %s", input.Timeout, root.Timeout)
+		}
+		if _, err := c.RunWithCommand("-timeout 1s sync -timeout 1m"); err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		if input.Timeout != time.Minute || root.Timeout != time.Second {
+			t.Errorf("Expected the flag before the command to stay global, got %s and root timeout %s", input.Timeout, root.Timeout)
+		}
+		if _, args, _ := c.Resolve([]string{"sync", "-timeout", "30s"}); len(args) != 2 {
+			t.Errorf("Expected Resolve to keep the declared flag, got %v", args)
+		}
+	})
+}
+
+func TestContextFunc(t *testing.T) {
+	type appContext struct {
+		Name string
+		DB   string
+	}
+	calls := 0
+	errConnect := errors.New("connection refused")
+	cmds := []*Command[*appContext]{
+		{
+			Use: "query",
+			ContextFunc: func(cmd *Command[*appContext], base *appContext) (*appContext, error) {
+				calls++
+				if base.Name == "offline" {
+					return nil, errConnect
+				}
+				enriched := *base
+				enriched.DB = "db:" + cmd.Use
+				return &enriched, nil
+			},
+			Run: func(cmd *Command[*appContext], args []string, ctx *appContext) (Data, error) {
+				return &DataMessage{Message: ctx.Name + " " + ctx.DB}, nil
+			},
+		},
+		{
+			Use: "version",
+			Run: func(cmd *Command[*appContext], args []string, ctx *appContext) (Data, error) {
+				return &DataMessage{Message: ctx.Name + " " + ctx.DB}, nil
+			},
+		},
+	}
+
+	t.Run("Enriched", func(t *testing.T) {
+		calls = 0
+		base := &appContext{Name: "app"}
+		data, err := Cli(base, cmds).RunWithCommand("query")
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+		if data.(*DataMessage).Message != "app db:query" {
+			t.Errorf("Expected enriched context, got %v", data)
+		}
+		if calls != 1 || base.DB != "" {
+			t.Errorf("Expected one call leaving the base unchanged, got %d calls and %+v", calls, base)
+		}
+	})
+
+	t.Run("Without", func(t *testing.T) {
+		calls = 0
+		data, err := Cli(&appContext{Name: "app"}, cmds).RunWithCommand("version")
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+		if data.(*DataMessage).Message != "app " {
+			t.Errorf("Expected base context, got %v", data)
+		}
+		if calls != 0 {
+			t.Errorf("Expected ContextFunc not to be called, got %d calls", calls)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := Cli(&appContext{Name: "offline"}, cmds).RunWithCommand("query")
+		var dataErr *DataError
+		if !errors.As(err, &dataErr) || !errors.Is(err, errConnect) {
+			t.Errorf("Expected DataError wrapping the error, got %#v", err)
+		}
+	})
+}
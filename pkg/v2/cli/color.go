@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"regexp"
+)
+
+const (
+	colorReset = "\033[0m"
+	colorRed   = "\033[31m"
+	colorBold  = "\033[1m"
+)
+
+// isTerminal reports whether w is a terminal. It is a variable so tests can
+// simulate a terminal.
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ColorEnabled reports whether formatters may emit ANSI escape codes. Color is
+// disabled by a non-empty NO_COLOR environment variable (see
+// https://no-color.org) or if stdout is not a terminal. Run additionally
+// removes the escape codes from its output if NoColor is set or its Writer is
+// not a terminal.
+func ColorEnabled() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(os.Stdout)
+}
+
+// colorize wraps s in the given escape code if color is enabled.
+func colorize(color string, s string) string {
+	if !ColorEnabled() {
+		return s
+	}
+	return color + s + colorReset
+}
+
+var escapeCodes = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// colorFor removes the escape codes from s if noColor or NO_COLOR is set or if
+// w is not a terminal.
+func colorFor(w io.Writer, s string, noColor bool) string {
+	if !noColor && os.Getenv("NO_COLOR") == "" && isTerminal(w) {
+		return s
+	}
+	return escapeCodes.ReplaceAllString(s, "")
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestColorEnabled(t *testing.T) {
+	terminal := isTerminal
+	defer func() { isTerminal = terminal }()
+	isTerminal = func(w io.Writer) bool { return w == os.Stdout }
+
+	t.Run("Terminal", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		if v := colorize(colorRed, "x"); v != colorRed+"x"+colorReset {
+			t.Errorf("Expected escape codes, got %q", v)
+		}
+	})
+
+	t.Run("NO_COLOR", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		if v := colorize(colorRed, "x"); v != "x" {
+			t.Errorf("Expected no escape codes, got %q", v)
+		}
+	})
+
+	t.Run("Flag", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		out := &bytes.Buffer{}
+		isTerminal = func(w io.Writer) bool { return w == os.Stdout || w == out }
+		defer func() { isTerminal = func(w io.Writer) bool { return w == os.Stdout } }()
+		cmds := []*Command[*Context]{
+			{
+				Use: "version",
+				Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+					return &DataMessage{Message: colorize(colorRed, "x")}, nil
+				},
+			},
+		}
+		c := Cli[*Context](&Context{}, cmds)
+		c.Writer = out
+		c.RunArgs([]string{"version", "--no-color"})
+		if out.String() != "x\n" {
+			t.Errorf("Expected no escape codes, got %q", out.String())
+		}
+
+		// the flag applies to its run only
+		out.Reset()
+		c.RunArgs([]string{"version"})
+		if out.String() != colorRed+"x"+colorReset+"\n" {
+			t.Errorf("Expected escape codes in the next run, got %q", out.String())
+		}
+		if c.NoColor {
+			t.Errorf("Expected the flag not to change the root")
+		}
+	})
+
+	t.Run("Writer", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		out := &bytes.Buffer{}
+		cmds := []*Command[*Context]{
+			{
+				Use: "version",
+				Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+					return &DataMessage{Message: colorize(colorRed, "x")}, nil
+				},
+			},
+		}
+		c := Cli[*Context](&Context{}, cmds)
+		c.Writer = out
+
+		c.RunArgs([]string{"version"})
+		if out.String() != "x\n" {
+			t.Errorf("Expected no escape codes for a writer that is not a terminal, got %q", out.String())
+		}
+
+		out.Reset()
+		isTerminal = func(w io.Writer) bool { return w == os.Stdout || w == out }
+		c.RunArgs([]string{"version"})
+		if out.String() != colorRed+"x"+colorReset+"\n" {
+			t.Errorf("Expected escape codes for a terminal writer, got %q", out.String())
+		}
+	})
+
+	t.Run("No terminal", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		isTerminal = func(w io.Writer) bool { return false }
+		if v := colorize(colorRed, "x"); v != "x" {
+			t.Errorf("Expected no escape codes, got %q", v)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+)
+
+// Comparison holds the values of a key on both sides of a DataCompare. A nil
+// value means the key is missing on that side.
+type Comparison struct {
+	Expected *string `json:"expected,omitempty"`
+	Actual   *string `json:"actual,omitempty"`
+	Match    bool    `json:"match"`
+}
+
+// DataCompare compares two maps side by side, e.g. the expected and the actual
+// configuration of a service. The text output renders a table with the key
+// and both values, mismatching rows are highlighted. The JSON output holds an
+// object with the values of each key and whether they match.
+//
+// Keys present on only one side are included, the missing value is rendered
+// empty. ExpectedLabel and ActualLabel are the column headers of the text
+// output, "expected" and "actual" if empty.
+type DataCompare struct {
+	Title         string
+	Expected      map[string]string
+	Actual        map[string]string
+	ExpectedLabel string
+	ActualLabel   string
+}
+
+// compareJSON is the JSON representation of a DataCompare.
+type compareJSON struct {
+	Title  string                `json:"title"`
+	Fields map[string]Comparison `json:"fields"`
+}
+
+func (d *DataCompare) Display(formatter Formatter) (string, error) {
+	if isJSON(formatter) {
+		return formatter.Format(&compareJSON{Title: d.Title, Fields: d.Comparisons()})
+	}
+	return formatter.Format(d)
+}
+
+// Comparisons returns the comparison of each key of both maps.
+func (d *DataCompare) Comparisons() map[string]Comparison {
+	comparisons := make(map[string]Comparison, len(d.Expected))
+	for k, v := range d.Expected {
+		comparisons[k] = Comparison{Expected: &v}
+	}
+	for k, v := range d.Actual {
+		c := comparisons[k]
+		c.Actual = &v
+		comparisons[k] = c
+	}
+	for k, c := range comparisons {
+		c.Match = c.Expected != nil && c.Actual != nil && *c.Expected == *c.Actual
+		comparisons[k] = c
+	}
+	return comparisons
+}
+
+func (d *DataCompare) Error() string {
+	comparisons := d.Comparisons()
+	keys := make([]string, 0, len(comparisons))
+	for k := range comparisons {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rows := make([][]string, len(keys))
+	for i, k := range keys {
+		c := comparisons[k]
+		rows[i] = []string{k, derefString(c.Expected), derefString(c.Actual)}
+	}
+	header := []string{humanizeKey("key"), humanizeKey(labelOr(d.ExpectedLabel, "expected")), humanizeKey(labelOr(d.ActualLabel, "actual"))}
+	lines := strings.Split(renderTable(header, rows), "\n")
+
+	// highlight the lines of the mismatching rows, skipping the header and
+	// the separator
+	line := 2
+	for i, k := range keys {
+		height := len(cellLines(rows[i]))
+		if !comparisons[k].Match {
+			for j := line; j < line+height; j++ {
+				lines[j] = colorize(colorRed, lines[j])
+			}
+		}
+		line += height
+	}
+	return titled(d.Title, strings.Join(lines, "\n"))
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func labelOr(label string, fallback string) string {
+	if label == "" {
+		return fallback
+	}
+	return label
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDataCompare(t *testing.T) {
+	data := &DataCompare{
+		Title:    "Config",
+		Expected: map[string]string{"host": "db", "port": "5432", "ssl": "on"},
+		Actual:   map[string]string{"host": "db", "port": "5433", "user": "app"},
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		v, _ := data.Display(&TextFormatter{})
+		expected := "Config\n" +
+			"Key   Expected  Actual\n" +
+			"----  --------  ------\n" +
+			"host  db        db\n" +
+			"port  5432      5433\n" +
+			"ssl   on        \n" +
+			"user            app"
+		if v != expected {
+			t.Errorf("Unexpected text output:\n%s", v)
+		}
+	})
+
+	t.Run("Labels", func(t *testing.T) {
+		labeled := *data
+		labeled.ExpectedLabel = "staging"
+		labeled.ActualLabel = "production"
+		v := labeled.Error()
+		if !strings.HasPrefix(v, "Config\nKey   Staging  Production\n") {
+			t.Errorf("Unexpected headers:\n%s", v)
+		}
+	})
+
+	t.Run("Highlight", func(t *testing.T) {
+		terminal := isTerminal
+		defer func() { isTerminal = terminal }()
+		isTerminal = func(w io.Writer) bool { return true }
+		t.Setenv("NO_COLOR", "")
+
+		v := (&DataCompare{
+			Expected: map[string]string{"a": "1", "b": "2"},
+			Actual:   map[string]string{"a": "1", "b": "3"},
+		}).Error()
+		expected := "Key  Expected  Actual\n" +
+			"---  --------  ------\n" +
+			"a    1         1\n" +
+			colorRed + "b    2         3" + colorReset
+		if v != expected {
+			t.Errorf("Unexpected text output: %q", v)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		v, _ := data.Display(&JSONFormatter{})
+		expected := `{"title":"Config","fields":{` +
+			`"host":{"expected":"db","actual":"db","match":true},` +
+			`"port":{"expected":"5432","actual":"5433","match":false},` +
+			`"ssl":{"expected":"on","match":false},` +
+			`"user":{"actual":"app","match":false}}}`
+		if v != expected {
+			t.Errorf("Unexpected JSON output: %s", v)
+		}
+	})
+}
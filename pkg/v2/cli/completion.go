@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CompletionShells lists the shells supported by the completion command.
+var CompletionShells = []string{"bash", "zsh"}
+
+// WithCompletion registers a "completion <shell>" command that prints the
+// completion script for the given shell.
+func WithCompletion[T any]() Option[T] {
+	return func(c *CliRoot[T]) {
+		c.Commands = append(c.Commands, &Command[T]{
+			Use:     "completion",
+			Short:   "Generate shell completion scripts",
+			Long:    "Generate the completion script for " + strings.Join(CompletionShells, " or "),
+			Example: "completion zsh",
+			Run: func(cmd *Command[T], args []string, ctx T) (Data, error) {
+				shell := ""
+				if len(args) > 0 {
+					shell = args[0]
+				}
+				var b strings.Builder
+				var err error
+				switch shell {
+				case "bash":
+					err = c.GenerateBashCompletion(&b)
+				case "zsh":
+					err = c.GenerateZshCompletion(&b)
+				default:
+					return nil, &DataError{
+						Message: fmt.Sprintf("unsupported shell %q, supported shells: %s", shell, strings.Join(CompletionShells, ", ")),
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				return &DataMessage{Message: b.String(), Raw: true}, nil
+			},
+		})
+	}
+}
+
+// GenerateBashCompletion writes a bash completion script for the command tree to w.
+func (c *CliRoot[T]) GenerateBashCompletion(w io.Writer) error {
+	name := c.programName()
+	fn := "_" + completionIdent(name) + "_completion"
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s\n", name)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur cmdpath\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    cmdpath=\"${COMP_WORDS[*]:1:COMP_CWORD-1}\"\n")
+	b.WriteString("    case \"$cmdpath\" in\n")
+	for _, level := range completionLevels(c.Commands) {
+		names := []string{}
+		for _, cmd := range level.commands {
+			names = append(names, cmd.Use)
+		}
+		for _, f := range level.flags {
+			names = append(names, f.names()...)
+		}
+		fmt.Fprintf(&b, "        %q)\n", level.path)
+		fmt.Fprintf(&b, "            COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(names, " "))
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, name)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// GenerateZshCompletion writes a zsh completion script for the command tree to w.
+func (c *CliRoot[T]) GenerateZshCompletion(w io.Writer) error {
+	name := c.programName()
+	fn := "_" + completionIdent(name)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n\n", name)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local -a commands flags\n")
+	b.WriteString("    local cmdpath=\"${(j: :)words[2,CURRENT-1]}\"\n")
+	b.WriteString("    case \"$cmdpath\" in\n")
+	for _, level := range completionLevels(c.Commands) {
+		fmt.Fprintf(&b, "        %q)\n", level.path)
+		b.WriteString("            commands=(\n")
+		for _, cmd := range level.commands {
+			fmt.Fprintf(&b, "                %s\n", zshQuote(cmd.Use+":"+strings.ReplaceAll(cmd.Short, ":", "\\:")))
+		}
+		b.WriteString("            )\n")
+		if len(level.flags) > 0 {
+			b.WriteString("            flags=(\n")
+			for _, f := range level.flags {
+				for _, name := range f.names() {
+					fmt.Fprintf(&b, "                %s\n", zshQuote(name+":"+strings.ReplaceAll(f.Description, ":", "\\:")))
+				}
+			}
+			b.WriteString("            )\n")
+		}
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("    _describe 'command' commands\n")
+	b.WriteString("    _describe 'flag' flags\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "compdef %s %s\n", fn, name)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (c *CliRoot[T]) programName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return filepath.Base(os.Args[0])
+}
+
+type completionLevel[T any] struct {
+	path     string
+	commands []*Command[T]
+	flags    []FlagSpec
+}
+
+// completionLevels returns the subcommands of each command path, and the flags
+// of each command with Flags, sorted by path.
+func completionLevels[T any](commands []*Command[T]) []completionLevel[T] {
+	levels := []completionLevel[T]{}
+	var walk func(path []string, commands []*Command[T])
+	walk = func(path []string, commands []*Command[T]) {
+		levels = append(levels, completionLevel[T]{path: strings.Join(path, " "), commands: commands})
+		for _, cmd := range commands {
+			cmdPath := append(append([]string{}, path...), cmd.Use)
+			if len(cmd.Commands) > 0 {
+				walk(cmdPath, cmd.Commands)
+			} else if len(cmd.Flags) > 0 {
+				levels = append(levels, completionLevel[T]{path: strings.Join(cmdPath, " "), flags: cmd.Flags})
+			}
+		}
+	}
+	walk(nil, commands)
+	sort.SliceStable(levels, func(i, j int) bool {
+		return levels[i].path < levels[j].path
+	})
+	return levels
+}
+
+var nonIdent = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+func completionIdent(name string) string {
+	return nonIdent.ReplaceAllString(name, "_")
+}
+
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func completionCommands() []*Command[*Context] {
+	return []*Command[*Context]{
+		{Use: "version", Short: "Print the version"},
+		{
+			Use:   "users",
+			Short: "Manage users",
+			Commands: []*Command[*Context]{
+				{
+					Use:   "list",
+					Short: "List users",
+					Flags: []FlagSpec{{Name: "limit", Short: "l", Description: "Maximum number: default 10"}},
+				},
+				{Use: "create", Short: "Create a user: admin's choice"},
+			},
+		},
+	}
+}
+
+func TestGenerateZshCompletion(t *testing.T) {
+	c := Cli[*Context](&Context{}, completionCommands())
+	c.Name = "mycli"
+
+	var b strings.Builder
+	if err := c.GenerateZshCompletion(&b); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	v := b.String()
+	for _, expected := range []string{
+		"#compdef mycli\n",
+		"        \"\")\n            commands=(\n                'version:Print the version'\n                'users:Manage users'\n            )",
+		"        \"users\")\n            commands=(\n                'list:List users'\n                'create:Create a user\\: admin'\\''s choice'\n            )",
+		"        \"users list\")\n            commands=(\n            )\n            flags=(\n                '-limit:Maximum number\\: default 10'\n                '-l:Maximum number\\: default 10'\n            )",
+		"compdef _mycli mycli\n",
+	} {
+		if !strings.Contains(v, expected) {
+			t.Errorf("Expected script to contain %q, got\n%s", expected, v)
+		}
+	}
+}
+
+func TestGenerateBashCompletion(t *testing.T) {
+	c := Cli[*Context](&Context{}, completionCommands())
+	c.Name = "my-cli"
+
+	var b strings.Builder
+	if err := c.GenerateBashCompletion(&b); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	v := b.String()
+	for _, expected := range []string{
+		`COMPREPLY=($(compgen -W "version users" -- "$cur"))`,
+		`COMPREPLY=($(compgen -W "list create" -- "$cur"))`,
+		"\"users list\")\n            COMPREPLY=($(compgen -W \"-limit -l\" -- \"$cur\"))",
+		"complete -F _my_cli_completion my-cli\n",
+	} {
+		if !strings.Contains(v, expected) {
+			t.Errorf("Expected script to contain %q, got\n%s", expected, v)
+		}
+	}
+}
+
+func TestCompletionCommand(t *testing.T) {
+	c := Cli[*Context](&Context{}, completionCommands(), WithCompletion[*Context]())
+	c.Name = "mycli"
+
+	data, err := c.RunWithCommand("completion zsh")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(data.(*DataMessage).Message, "#compdef mycli") {
+		t.Errorf("Expected zsh script, got %v", data)
+	}
+
+	t.Run("Wrapped", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, completionCommands(), WithCompletion[*Context]())
+		c.Name = "mycli"
+		c.Formatter = &TextFormatter{WrapWidth: 20}
+		out := &bytes.Buffer{}
+		c.Writer = out
+		c.RunArgs([]string{"completion", "bash"})
+
+		var script strings.Builder
+		c.GenerateBashCompletion(&script)
+		if out.String() != script.String()+"\n" {
+			t.Errorf("Expected the script not to be wrapped, got:\n%s", out.String())
+		}
+	})
+
+	_, err = c.RunWithCommand("completion fish")
+	var dataErr *DataError
+	if !errors.As(err, &dataErr) {
+		t.Fatalf("Expected DataError, got %v", err)
+	}
+	if !strings.Contains(dataErr.Message, "bash, zsh") {
+		t.Errorf("Expected supported shells in error, got %s", dataErr.Message)
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// DataComposite combines several results into one output, e.g. a message, a
+// list and details for a dashboard. The text output renders the items in
+// order separated by blank lines, the JSON output an array holding the JSON
+// output of each item and the NDJSON output the lines of all items.
+type DataComposite struct {
+	Items []Data
+}
+
+func (d *DataComposite) Display(formatter Formatter) (string, error) {
+	if formatter.Type() == "json" {
+		items := []json.RawMessage{}
+		for _, item := range d.Items {
+			v, err := d.jsonItem(item, formatter)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, json.RawMessage(v))
+		}
+		return formatter.Format(items)
+	}
+
+	separator := "\n\n"
+	if isJSON(formatter) {
+		separator = "\n"
+	}
+	a := []string{}
+	for _, item := range d.Items {
+		v, err := item.Display(formatter)
+		if err != nil {
+			return "", err
+		}
+		a = append(a, v)
+	}
+	return strings.Join(a, separator), nil
+}
+
+// jsonItem returns the JSON output of the item. A DataMessage displays its
+// text for all formatters, so it is always marshaled with the message as a
+// string, e.g. {"message":"42"}. Other items are embedded as displayed if that
+// is JSON.
+func (d *DataComposite) jsonItem(item Data, formatter Formatter) (string, error) {
+	if _, ok := item.(*DataMessage); ok {
+		return formatter.Format(item)
+	}
+	v, err := item.Display(formatter)
+	if err != nil {
+		return "", err
+	}
+	if !json.Valid([]byte(v)) {
+		return formatter.Format(item)
+	}
+	return v, nil
+}
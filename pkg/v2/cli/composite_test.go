@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestDataComposite(t *testing.T) {
+	data := &DataComposite{
+		Items: []Data{
+			&DataMessage{Message: "Dashboard"},
+			&DataList{
+				Title: "Users",
+				Items: []map[string]string{{"name": "Max"}},
+			},
+			&DataDetails{
+				Title: "Stats",
+				Item:  map[string]string{"active": "1"},
+			},
+		},
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		v, err := data.Display(&TextFormatter{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		list, _ := data.Items[1].Display(&TextFormatter{})
+		details, _ := data.Items[2].Display(&TextFormatter{})
+		if v != "Dashboard\n\n"+list+"\n\n"+details {
+			t.Errorf("Unexpected text output: %q", v)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		v, err := data.Display(&JSONFormatter{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		expected := `[{"message":"Dashboard"},{"title":"Users","items":[{"name":"Max"}]},{"title":"Stats","item":{"active":"1"}}]`
+		if v != expected {
+			t.Errorf("Expected %s, got %s", expected, v)
+		}
+	})
+	t.Run("JSONMessage", func(t *testing.T) {
+		v, err := (&DataComposite{Items: []Data{
+			&DataMessage{Message: "42"},
+			&DataMessage{Message: "true"},
+			&DataMessage{Message: `{"a":1}`},
+		}}).Display(&JSONFormatter{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		expected := `[{"message":"42"},{"message":"true"},{"message":"{\"a\":1}"}]`
+		if v != expected {
+			t.Errorf("Expected %s, got %s", expected, v)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CSVFormatter implements Formatter to output DataList and DataDetails as CSV.
+// Other data is formatted like the TextFormatter.
+//
+// Delimiter defaults to a comma. Set it to '\t' to output TSV. Fields
+// containing the delimiter, quotes or line breaks are quoted.
+type CSVFormatter struct {
+	Delimiter rune
+}
+
+func (f *CSVFormatter) Format(data interface{}) (string, error) {
+	var records [][]string
+	switch d := data.(type) {
+	case *DataList:
+		columns := d.csvColumns()
+		records = append(records, columns)
+		for _, item := range d.Items {
+			row := []string{}
+			for _, column := range columns {
+				row = append(row, item[column])
+			}
+			records = append(records, row)
+		}
+	case *DataDetails:
+		records = append(records, []string{"key", "value"})
+		for _, k := range sortedKeys(d.Item) {
+			records = append(records, []string{k, d.Item[k]})
+		}
+	default:
+		return fmt.Sprintf("%v", data), nil
+	}
+
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if f.Delimiter != 0 {
+		w.Comma = f.Delimiter
+	}
+	if err := w.WriteAll(records); err != nil {
+		return "", fmt.Errorf("error formatting %T as csv: %w", data, err)
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+func (f *CSVFormatter) Type() string {
+	if f.Delimiter == '\t' {
+		return "tsv"
+	}
+	return "csv"
+}
+
+// isCSV reports whether the formatter writes CSV or TSV.
+func isCSV(formatter Formatter) bool {
+	t := formatter.Type()
+	return t == "csv" || t == "tsv"
+}
+
+// withGroupColumn returns the list with the GroupBy key added to the Columns
+// if they are set and do not contain it.
+func (d *DataList) withGroupColumn() *DataList {
+	if d.GroupBy == "" || len(d.Columns) == 0 || slices.Contains(d.Columns, d.GroupBy) {
+		return d
+	}
+	grouped := *d
+	grouped.Columns = append(append([]string{}, d.Columns...), d.GroupBy)
+	return &grouped
+}
+
+func (d *DataList) csvColumns() []string {
+	if len(d.Columns) == 0 {
+		return listColumns(d.Items)
+	}
+	return d.Columns
+}
+
+// WriteCSV writes the list as CSV with the same columns as the CSVFormatter,
+// writing row by row instead of building the output in memory, e.g. into an
+// http.ResponseWriter.
+func (d *DataList) WriteCSV(w io.Writer) error {
+	columns := d.withGroupColumn().csvColumns()
+	offset := 0
+	if d.ShowIndex {
+		if d.Page > 0 && d.PageSize > 0 {
+			offset = (d.Page - 1) * d.PageSize
+		}
+		columns = append([]string{IndexColumn}, columns...)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return fmt.Errorf("error writing csv: %w", err)
+	}
+	for i, item := range d.Items {
+		row := make([]string, 0, len(columns))
+		for _, column := range columns {
+			if d.ShowIndex && column == IndexColumn {
+				row = append(row, strconv.Itoa(offset+i+1))
+				continue
+			}
+			row = append(row, item[column])
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("error writing csv: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("error writing csv: %w", err)
+	}
+	return nil
+}
+
+// WriteJSON writes the list as JSON like the JSONFormatter, encoding item by
+// item instead of building the output in memory. GroupBy is ignored.
+func (d *DataList) WriteJSON(w io.Writer) error {
+	title, _ := json.Marshal(d.Title)
+	if err := writeAll(w, []byte(`{"title":`), title, []byte(`,"items":`)); err != nil {
+		return err
+	}
+
+	if d.Items == nil {
+		if err := writeAll(w, []byte("null")); err != nil {
+			return err
+		}
+	} else {
+		if err := writeAll(w, []byte("[")); err != nil {
+			return err
+		}
+		for i, item := range d.Items {
+			var value interface{} = item
+			if d.OrderedJSON {
+				value = newOrderedMap(item, d.Columns)
+			}
+			v, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("error writing json: %w", err)
+			}
+			if i > 0 {
+				v = append([]byte(","), v...)
+			}
+			if err := writeAll(w, v); err != nil {
+				return err
+			}
+		}
+		if err := writeAll(w, []byte("]")); err != nil {
+			return err
+		}
+	}
+
+	tail := ""
+	if d.Total != 0 {
+		tail += fmt.Sprintf(`,"total":%d`, d.Total)
+	}
+	if d.Page != 0 {
+		tail += fmt.Sprintf(`,"page":%d`, d.Page)
+	}
+	if d.PageSize != 0 {
+		tail += fmt.Sprintf(`,"page_size":%d`, d.PageSize)
+	}
+	return writeAll(w, []byte(tail+"}"))
+}
+
+func writeAll(w io.Writer, parts ...[]byte) error {
+	for _, p := range parts {
+		if _, err := w.Write(p); err != nil {
+			return fmt.Errorf("error writing json: %w", err)
+		}
+	}
+	return nil
+}
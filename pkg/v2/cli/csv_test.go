@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCSVFormatter(t *testing.T) {
+	data := &DataList{
+		Title: "Users",
+		Items: []map[string]string{
+			{"id": "1", "name": "Mustermann, Max"},
+			{"id": "2", "name": "tab\tseparated"},
+		},
+	}
+
+	t.Run("Comma", func(t *testing.T) {
+		v, err := data.Display(&CSVFormatter{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := "id,name\n1,\"Mustermann, Max\"\n2,tab\tseparated"
+		if v != expected {
+			t.Errorf("Expected %q, got %q", expected, v)
+		}
+	})
+
+	t.Run("Tab", func(t *testing.T) {
+		v, err := data.Display(&CSVFormatter{Delimiter: '\t'})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := "id\tname\n1\tMustermann, Max\n2\t\"tab\tseparated\""
+		if v != expected {
+			t.Errorf("Expected %q, got %q", expected, v)
+		}
+	})
+
+	t.Run("DataDetails", func(t *testing.T) {
+		v, _ := (&DataDetails{Item: map[string]string{"b": "2", "a": "1"}}).Display(&CSVFormatter{})
+		if v != "key,value\na,1\nb,2" {
+			t.Errorf("Unexpected output: %q", v)
+		}
+	})
+
+	t.Run("GroupBy", func(t *testing.T) {
+		grouped := &DataList{
+			Title: "Users",
+			Items: []map[string]string{
+				{"name": "Max", "role": "admin"},
+				{"name": "Erika"},
+				{"name": "Anna", "role": "member"},
+			},
+			Columns: []string{"name"},
+			GroupBy: "role",
+		}
+		v, err := grouped.Display(&CSVFormatter{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := "name,role\nMax,admin\nErika,\nAnna,member"
+		if v != expected {
+			t.Errorf("Expected %q, got %q", expected, v)
+		}
+
+		var b bytes.Buffer
+		if err := grouped.WriteCSV(&b); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if b.String() != expected+"\n" {
+			t.Errorf("Expected WriteCSV to match, got %q", b.String())
+		}
+	})
+
+	t.Run("Flags", func(t *testing.T) {
+		cmds := []*Command[*Context]{{Use: "list", Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+			return data, nil
+		}}}
+		c := Cli[*Context](&Context{}, cmds)
+		out := &bytes.Buffer{}
+		c.Writer = out
+		c.RunArgs([]string{"list", "-tsv"})
+		if out.String() != "id\tname\n1\tMustermann, Max\n2\t\"tab\tseparated\"\n" {
+			t.Errorf("Expected tsv output, got %q", out.String())
+		}
+		out.Reset()
+		c.RunArgs([]string{"list", "-csv"})
+		if out.String() != "id,name\n1,\"Mustermann, Max\"\n2,tab\tseparated\n" {
+			t.Errorf("Expected csv output, got %q", out.String())
+		}
+	})
+}
+
+func TestDataListWriters(t *testing.T) {
+	lists := map[string]*DataList{
+		"Items": {
+			Title: "Users \"all\"",
+			Items: []map[string]string{
+				{"id": "1", "name": "Mustermann, Max"},
+				{"id": "2", "name": "Erika"},
+			},
+			Total:    12,
+			Page:     2,
+			PageSize: 2,
+		},
+		"Index":   {Items: []map[string]string{{"id": "1"}}, ShowIndex: true, Page: 2, PageSize: 5},
+		"Columns": {Items: []map[string]string{{"id": "1", "name": "Max"}}, Columns: []string{"name"}},
+		"Empty":   {Items: []map[string]string{}},
+		"Nil":     {},
+	}
+
+	for name, data := range lists {
+		t.Run(name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := data.WriteCSV(&b); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected, _ := data.Display(&CSVFormatter{})
+			if b.String() != expected+"\n" {
+				t.Errorf("Expected %q, got %q", expected+"\n", b.String())
+			}
+
+			b.Reset()
+			if err := data.WriteJSON(&b); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected, _ = data.Display(&JSONFormatter{})
+			if b.String() != expected {
+				t.Errorf("Expected %s, got %s", expected, b.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DataDetailsAny works like DataDetails but holds values of any type. Nested
+// maps and slices are rendered as indented blocks in text output and as nested
+// objects and arrays in JSON output.
+type DataDetailsAny struct {
+	Title string                 `json:"title"`
+	Item  map[string]interface{} `json:"item"`
+}
+
+func (d *DataDetailsAny) Display(formatter Formatter) (string, error) {
+	return formatter.Format(d)
+}
+
+func (d *DataDetailsAny) Error() string {
+	a := []string{d.Title}
+	a = appendValue(a, "", reflect.ValueOf(d.Item))
+	return strings.Join(a, "\n")
+}
+
+// appendValue appends the lines of the map or slice v with the given indent.
+func appendValue(lines []string, indent string, v reflect.Value) []string {
+	v = indirect(v)
+	switch v.Kind() {
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := map[string]reflect.Value{}
+		for _, k := range v.MapKeys() {
+			key := fmt.Sprint(k.Interface())
+			keys = append(keys, key)
+			values[key] = v.MapIndex(k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			lines = appendEntry(lines, indent, k+":", values[k])
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			lines = appendEntry(lines, indent, "-", v.Index(i))
+		}
+	}
+	return lines
+}
+
+// appendEntry appends a single map entry or slice element. Scalars are
+// rendered on the same line as the label, maps and slices below it.
+func appendEntry(lines []string, indent string, label string, v reflect.Value) []string {
+	v = indirect(v)
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		lines = append(lines, indent+label)
+		return appendValue(lines, indent+"  ", v)
+	case reflect.Invalid:
+		return append(lines, indent+label)
+	}
+	return append(lines, fmt.Sprintf("%s%s %v", indent, label, v.Interface()))
+}
+
+// indirect resolves interfaces and pointers to the underlying value.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
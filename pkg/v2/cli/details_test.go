@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestDataDetailsAny(t *testing.T) {
+	data := &DataDetailsAny{
+		Title: "User",
+		Item: map[string]interface{}{
+			"name": "Max",
+			"address": map[string]interface{}{
+				"city": "Berlin",
+				"zip":  10115,
+			},
+			"roles": []string{"admin", "member"},
+			"teams": []interface{}{
+				map[string]string{"name": "core"},
+			},
+			"manager": nil,
+		},
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		v, _ := data.Display(&TextFormatter{})
+		expected := "User\n" +
+			"address:\n" +
+			"  city: Berlin\n" +
+			"  zip: 10115\n" +
+			"manager:\n" +
+			"name: Max\n" +
+			"roles:\n" +
+			"  - admin\n" +
+			"  - member\n" +
+			"teams:\n" +
+			"  -\n" +
+			"    name: core"
+		if v != expected {
+			t.Errorf("Unexpected text output:\n%s", v)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		v, _ := data.Display(&JSONFormatter{})
+		expected := `{"title":"User","item":{"address":{"city":"Berlin","zip":10115},"manager":null,"name":"Max","roles":["admin","member"],"teams":[{"name":"core"}]}}`
+		if v != expected {
+			t.Errorf("Unexpected JSON output: %s", v)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+)
+
+// Change holds the old and the new value of a field.
+type Change struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// Changed reports whether the value of the field changed.
+func (c Change) Changed() bool {
+	return c.Old != c.New
+}
+
+// DataDiff shows the changes of a resource, e.g. after an update command. The
+// text output renders one "field: old → new" line per field, the JSON output
+// an object with the old and new values of each field.
+//
+// If HideUnchanged is set, fields with equal old and new values are omitted.
+type DataDiff struct {
+	Title         string            `json:"title"`
+	Changes       map[string]Change `json:"changes"`
+	HideUnchanged bool              `json:"-"`
+}
+
+func (d *DataDiff) Display(formatter Formatter) (string, error) {
+	if isJSON(formatter) {
+		return formatter.Format(&DataDiff{Title: d.Title, Changes: d.visible()})
+	}
+	return formatter.Format(d)
+}
+
+func (d *DataDiff) Error() string {
+	changes := d.visible()
+	keys := make([]string, 0, len(changes))
+	for k := range changes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	a := []string{d.Title}
+	for _, k := range keys {
+		c := changes[k]
+		line := k + ": " + c.Old + " → " + c.New
+		if c.Changed() {
+			line = colorize(colorBold, line)
+		}
+		a = append(a, line)
+	}
+	return strings.Join(a, "\n")
+}
+
+func (d *DataDiff) visible() map[string]Change {
+	if !d.HideUnchanged {
+		return d.Changes
+	}
+	changes := map[string]Change{}
+	for k, c := range d.Changes {
+		if c.Changed() {
+			changes[k] = c
+		}
+	}
+	return changes
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestDataDiff(t *testing.T) {
+	data := &DataDiff{
+		Title: "User",
+		Changes: map[string]Change{
+			"email": {Old: "a@example.com", New: "b@example.com"},
+			"name":  {Old: "Max", New: "Max"},
+		},
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		v, _ := data.Display(&TextFormatter{})
+		if v != "User\nemail: a@example.com → b@example.com\nname: Max → Max" {
+			t.Errorf("Unexpected text output: %q", v)
+		}
+	})
+
+	t.Run("Hide unchanged", func(t *testing.T) {
+		hidden := *data
+		hidden.HideUnchanged = true
+
+		v, _ := hidden.Display(&TextFormatter{})
+		if v != "User\nemail: a@example.com → b@example.com" {
+			t.Errorf("Unexpected text output: %q", v)
+		}
+
+		v, _ = hidden.Display(&JSONFormatter{})
+		if v != `{"title":"User","changes":{"email":{"old":"a@example.com","new":"b@example.com"}}}` {
+			t.Errorf("Unexpected JSON output: %s", v)
+		}
+	})
+}
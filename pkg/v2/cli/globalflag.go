@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GlobalFlag is a flag accepted at any position of the args, before, between
+// or after the commands. It is removed from the args before dispatching and
+// its Handler is called instead. After the command name, a flag with the same
+// name in the Flags or KnownFlags of the command is left to the command, e.g.
+// a -timeout bound with InputFromModel.
+type GlobalFlag[T any] struct {
+	// Name is the name of the flag without dashes, it is accepted with one or
+	// two dashes, e.g. -name and --name.
+	Name string
+	// TakesValue reports whether the flag takes a value, either as the next
+	// arg or after =, e.g. -timeout 30s and -timeout=30s. Flags taking no
+	// value only accept it after =, e.g. -debug=false.
+	TakesValue bool
+	// Handler is called with the value of the flag, or "" for a bare flag
+	// taking no value. A returned error aborts the dispatching.
+	Handler func(root *CliRoot[T], value string) error
+}
+
+// RegisterGlobalFlag adds a global flag. Registered flags take precedence over
+// the built-in flags with the same name.
+func (c *CliRoot[T]) RegisterGlobalFlag(flag GlobalFlag[T]) {
+	c.globalFlags = append(c.globalFlags, flag)
+}
+
+// WithGlobalFlag registers a global flag, see RegisterGlobalFlag.
+func WithGlobalFlag[T any](flag GlobalFlag[T]) Option[T] {
+	return func(c *CliRoot[T]) {
+		c.RegisterGlobalFlag(flag)
+	}
+}
+
+// builtinGlobalFlags returns the global flags every CLI accepts. The handlers
+// only change the root they are called with, which is the copy of the root
+// made for the run, see invocation.
+func builtinGlobalFlags[T any]() []GlobalFlag[T] {
+	set := func(f func(c *CliRoot[T], on bool)) func(c *CliRoot[T], value string) error {
+		return func(c *CliRoot[T], value string) error {
+			on, err := boolFlag(value)
+			if err != nil {
+				return err
+			}
+			f(c, on)
+			return nil
+		}
+	}
+	output := func(name string) func(c *CliRoot[T], value string) error {
+		return func(c *CliRoot[T], value string) error {
+			on, err := boolFlag(value)
+			if err != nil || !on {
+				return err
+			}
+			f, err := formatterFor(name)
+			if err != nil {
+				return err
+			}
+			c.setFormatter(f)
+			return nil
+		}
+	}
+	return []GlobalFlag[T]{
+		{Name: "timeout", TakesValue: true, Handler: func(c *CliRoot[T], value string) error {
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				return &DataError{Message: fmt.Sprintf("invalid timeout %q, expected a duration such as 30s", value), Err: err}
+			}
+			c.Timeout = timeout
+			return nil
+		}},
+		{Name: "dry-run", Handler: set(func(c *CliRoot[T], on bool) { c.DryRun = on })},
+		{Name: "debug", Handler: set(func(c *CliRoot[T], on bool) { c.Debug = on })},
+		{Name: "quiet", Handler: set(func(c *CliRoot[T], on bool) { c.Quiet = on })},
+		{Name: "no-color", Handler: set(func(c *CliRoot[T], on bool) { c.NoColor = on })},
+		{Name: "output", TakesValue: true, Handler: func(c *CliRoot[T], value string) error {
+			f, err := formatterFor(value)
+			if err != nil {
+				return err
+			}
+			c.setFormatter(f)
+			return nil
+		}},
+		{Name: "csv", Handler: output("csv")},
+		{Name: "tsv", Handler: output("tsv")},
+		{Name: "ndjson", Handler: output("ndjson")},
+		{Name: "text", Handler: output("text")},
+		{Name: "json", Handler: output("json")},
+	}
+}
+
+// boolFlag parses the value of a flag taking no value, see ParseBool.
+func boolFlag(value string) (bool, error) {
+	on, ok := ParseBool(value)
+	if !ok {
+		return false, &DataError{Message: fmt.Sprintf("invalid value %q, expected a boolean such as true or false", value)}
+	}
+	return on, nil
+}
+
+// globalFlag returns the global flag matching the arg and the value given
+// after =, if any.
+func (c *CliRoot[T]) globalFlag(arg string) (flag *GlobalFlag[T], value string, inline bool, ok bool) {
+	if !strings.HasPrefix(arg, "-") {
+		return nil, "", false, false
+	}
+	name, value, inline := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-"), "=")
+	for _, flags := range [][]GlobalFlag[T]{c.globalFlags, builtinGlobalFlags[T]()} {
+		for i := range flags {
+			if flags[i].Name == name {
+				return &flags[i], value, inline, true
+			}
+		}
+	}
+	return nil, "", false, false
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestGlobalFlag(t *testing.T) {
+	var gotArgs []string
+	cmds := []*Command[*Context]{
+		{
+			Use: "users",
+			Commands: []*Command[*Context]{
+				{
+					Use: "list",
+					Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+						gotArgs = args
+						return nil, nil
+					},
+				},
+			},
+		},
+	}
+
+	for _, args := range [][]string{
+		{"-region", "eu", "users", "list", "a"},
+		{"users", "--region=eu", "list", "a"},
+		{"users", "list", "a", "-region", "eu"},
+	} {
+		region := ""
+		c := Cli[*Context](&Context{}, cmds, WithGlobalFlag(GlobalFlag[*Context]{
+			Name:       "region",
+			TakesValue: true,
+			Handler: func(root *CliRoot[*Context], value string) error {
+				region = value
+				return nil
+			},
+		}))
+		c.Writer = &bytes.Buffer{}
+		if code := c.RunArgs(args); code != 0 {
+			t.Errorf("Expected exit code 0 for %v, got %d", args, code)
+		}
+		if region != "eu" {
+			t.Errorf("Expected region eu for %v, got %q", args, region)
+		}
+		if len(gotArgs) != 1 || gotArgs[0] != "a" {
+			t.Errorf("Expected args [a] for %v, got %v", args, gotArgs)
+		}
+	}
+
+	t.Run("OverridesBuiltin", func(t *testing.T) {
+		called := false
+		c := Cli[*Context](&Context{}, cmds)
+		c.RegisterGlobalFlag(GlobalFlag[*Context]{
+			Name: "debug",
+			Handler: func(root *CliRoot[*Context], value string) error {
+				called = true
+				return nil
+			},
+		})
+		c.Writer = &bytes.Buffer{}
+		c.RunArgs([]string{"users", "list", "-debug"})
+		if !called || c.Debug {
+			t.Errorf("Expected the registered handler to replace the built-in flag")
+		}
+	})
+
+	t.Run("MissingValue", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds, WithGlobalFlag(GlobalFlag[*Context]{
+			Name:       "region",
+			TakesValue: true,
+			Handler:    func(root *CliRoot[*Context], value string) error { return nil },
+		}))
+		_, err := c.runCommand(c.Commands, []string{"users", "list", "-region"})
+		if err == nil || err.Error() != "missing value for flag -region" {
+			t.Errorf("Expected missing value error, got %v", err)
+		}
+	})
+}
+
+func TestGlobalFlagBoolValue(t *testing.T) {
+	cmds := []*Command[*Context]{
+		{
+			Use: "deploy",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return nil, nil
+			},
+		},
+	}
+	c := Cli[*Context](&Context{}, cmds)
+	if _, err := c.runCommand(c.Commands, []string{"deploy", "-dry-run=yes", "-debug=off"}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if !c.DryRun || c.Debug {
+		t.Errorf("Expected DryRun true and Debug false, got %v and %v", c.DryRun, c.Debug)
+	}
+	if _, err := c.runCommand(c.Commands, []string{"deploy", "-quiet=maybe"}); err == nil {
+		t.Errorf("Expected an error for an invalid boolean")
+	}
+}
+
+func TestBuiltinGlobalFlagsInvocation(t *testing.T) {
+	var seen *CliRoot[*Context]
+	cmds := []*Command[*Context]{
+		{
+			Use: "deploy",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				seen = cmd.Root()
+				return nil, nil
+			},
+		},
+	}
+	c := Cli[*Context](&Context{}, cmds)
+	c.Writer = &bytes.Buffer{}
+	formatter := c.Formatter
+	c.RunArgs([]string{"deploy", "-dry-run", "-debug", "-quiet", "-no-color", "-timeout", "1m", "-json"})
+
+	if !seen.DryRun || !seen.Debug || !seen.Quiet || !seen.NoColor || seen.Timeout != time.Minute || !isJSON(seen.Formatter) {
+		t.Errorf("Expected the flags to set the state of the run")
+	}
+	if c.DryRun || c.Debug || c.Quiet || c.NoColor || c.Timeout != 0 || c.Formatter != formatter {
+		t.Errorf("Expected the flags not to change the root")
+	}
+}
@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultHelpGroup is the heading used for commands without a Group.
+const DefaultHelpGroup = "Commands"
+
+// DataHelp holds the list of available commands organized in groups. Each item
+// has the keys "Use", "Short" and "Group". The text output renders one section
+// per group in order of first appearance, preserving the order of the commands
+// within each group.
+type DataHelp struct {
+	Title string              `json:"title"`
+	Items []map[string]string `json:"items"`
+}
+
+func (d *DataHelp) Display(formatter Formatter) (string, error) {
+	return formatter.Format(d)
+}
+
+func (d *DataHelp) Error() string {
+	groups := []string{}
+	sections := map[string][]map[string]string{}
+	width := useWidth(d.Items)
+	for _, item := range d.Items {
+		group := item["Group"]
+		if group == "" {
+			group = DefaultHelpGroup
+		}
+		if _, ok := sections[group]; !ok {
+			groups = append(groups, group)
+		}
+		sections[group] = append(sections[group], item)
+	}
+
+	a := []string{d.Title}
+	for _, group := range groups {
+		a = append(a, "", group+":")
+		a = append(a, commandLines(sections[group], width)...)
+	}
+	return strings.Join(a, "\n")
+}
+
+// DataCommandHelp holds the detailed help of a single command.
+type DataCommandHelp struct {
+	Use      string              `json:"use"`
+	Short    string              `json:"short,omitempty"`
+	Long     string              `json:"long,omitempty"`
+	Example  string              `json:"example,omitempty"`
+	Examples []string            `json:"examples,omitempty"`
+	Flags    []FlagSpec          `json:"flags,omitempty"`
+	Commands []map[string]string `json:"commands,omitempty"`
+}
+
+// FlagSpec describes a flag of a command. It is only used for documentation,
+// the flags are still parsed with ParseArgs.
+type FlagSpec struct {
+	// Name is the name of the flag without leading dashes, e.g. "limit".
+	Name string `json:"name"`
+	// Short is an optional one letter alias, e.g. "l".
+	Short       string `json:"short,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Default     string `json:"default,omitempty"`
+}
+
+// names returns the flag and its alias with a leading dash.
+func (f FlagSpec) names() []string {
+	names := []string{"-" + f.Name}
+	if f.Short != "" {
+		names = append(names, "-"+f.Short)
+	}
+	return names
+}
+
+func (f FlagSpec) usage() string {
+	a := []string{}
+	if f.Description != "" {
+		a = append(a, f.Description)
+	}
+	if f.Required {
+		a = append(a, "(required)")
+	}
+	if f.Default != "" {
+		a = append(a, "(default: "+f.Default+")")
+	}
+	return strings.Join(a, " ")
+}
+
+// RenderExamples returns the Example and the Examples of the command, each on
+// its own line with a "$ " prompt.
+func (cmd *Command[T]) RenderExamples() string {
+	return renderExamples(cmd.Example, cmd.Examples)
+}
+
+func renderExamples(example string, examples []string) string {
+	if example != "" {
+		examples = append([]string{example}, examples...)
+	}
+	lines := make([]string, len(examples))
+	for i, e := range examples {
+		lines[i] = "$ " + e
+	}
+	return strings.Join(lines, "\n")
+}
+
+// knownFlags returns the KnownFlags and the names of the Flags of the command.
+func (cmd *Command[T]) knownFlags() []string {
+	known := append([]string{}, cmd.KnownFlags...)
+	for _, f := range cmd.Flags {
+		known = append(known, f.Name)
+		if f.Short != "" {
+			known = append(known, f.Short)
+		}
+	}
+	return known
+}
+
+func (d *DataCommandHelp) Display(formatter Formatter) (string, error) {
+	return formatter.Format(d)
+}
+
+func (d *DataCommandHelp) Error() string {
+	a := []string{d.Use}
+	if d.Long != "" {
+		a = append(a, "", d.Long)
+	} else if d.Short != "" {
+		a = append(a, "", d.Short)
+	}
+	if len(d.Examples) > 0 {
+		a = append(a, "", "Examples:")
+		for _, line := range strings.Split(renderExamples(d.Example, d.Examples), "\n") {
+			a = append(a, "  "+line)
+		}
+	} else if d.Example != "" {
+		a = append(a, "", "Example:", "  "+d.Example)
+	}
+	if len(d.Flags) > 0 {
+		items := []map[string]string{}
+		for _, f := range d.Flags {
+			items = append(items, map[string]string{
+				"Use":   strings.Join(f.names(), ", "),
+				"Short": f.usage(),
+			})
+		}
+		a = append(a, "", "Flags:")
+		a = append(a, commandLines(items, useWidth(items))...)
+	}
+	if len(d.Commands) > 0 {
+		a = append(a, "", "Commands:")
+		a = append(a, commandLines(d.Commands, useWidth(d.Commands))...)
+	}
+	return strings.Join(a, "\n")
+}
+
+// useWidth returns the display width of the longest "Use" value of the items.
+func useWidth(items []map[string]string) int {
+	width := 0
+	for _, item := range items {
+		if w := displayWidth(item["Use"]); w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+// commandLines renders one indented line per command with the short
+// descriptions aligned at the given width.
+func commandLines(items []map[string]string, width int) []string {
+	lines := []string{}
+	for _, item := range items {
+		use := item["Use"]
+		line := fmt.Sprintf("  %s%s  %s", use, strings.Repeat(" ", width-displayWidth(use)), item["Short"])
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	return lines
+}
+
+// HelpFor returns the detailed help of the command at the given path, e.g.
+// []string{"users", "create"}. The list of all commands is returned for an
+// empty path. It is used by the "help <path...>" invocation.
+func (c *CliRoot[T]) HelpFor(path []string) (Data, error) {
+	if len(path) == 0 {
+		return c.Help(c.Commands)
+	}
+
+	commands := c.Commands
+	var target *Command[T]
+	for i, name := range path {
+		target = nil
+		for _, cmd := range commands {
+			if cmd.Use == name {
+				target = cmd
+				break
+			}
+		}
+		if target == nil {
+			return nil, fmt.Errorf("unknown help topic: %s", strings.Join(path[:i+1], " "))
+		}
+		commands = target.Commands
+	}
+
+	data := &DataCommandHelp{
+		Use:      strings.Join(path, " "),
+		Short:    target.Short,
+		Long:     target.Long,
+		Example:  target.Example,
+		Examples: target.Examples,
+		Flags:    target.Flags,
+	}
+	for _, cmd := range target.Commands {
+		data.Commands = append(data.Commands, map[string]string{
+			"Use":   cmd.Use,
+			"Short": cmd.Short,
+		})
+	}
+	return data, nil
+}
@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHelpGroups(t *testing.T) {
+	cmds := []*Command[*Context]{
+		{Use: "users", Short: "Manage users", Group: "Resources"},
+		{Use: "version", Short: "Print the version"},
+		{Use: "orders", Short: "Manage orders", Group: "Resources"},
+		{Use: "login", Short: "Log in", Group: "Auth"},
+	}
+	c := Cli[*Context](&Context{}, cmds)
+
+	t.Run("Text", func(t *testing.T) {
+		data, err := c.Help(cmds)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		v, _ := data.Display(&TextFormatter{})
+		expected := strings.Join([]string{
+			"Available commands",
+			"",
+			"Resources:",
+			"  users    Manage users",
+			"  orders   Manage orders",
+			"",
+			"Commands:",
+			"  version  Print the version",
+			"",
+			"Auth:",
+			"  login    Log in",
+		}, "\n")
+		if v != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, v)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		data, _ := c.Help(cmds)
+		v, _ := data.Display(&JSONFormatter{})
+		if !strings.Contains(v, `"Group":"Resources"`) {
+			t.Errorf("Expected group in JSON output, got %s", v)
+		}
+	})
+
+	t.Run("Ungrouped", func(t *testing.T) {
+		cmds := []*Command[*Context]{{Use: "version"}}
+		data, _ := c.Help(cmds)
+		if _, ok := data.(*DataList); !ok {
+			t.Errorf("Expected DataList without groups, got %T", data)
+		}
+	})
+}
+
+func TestHelpFor(t *testing.T) {
+	cmds := []*Command[*Context]{
+		{Use: "version", Short: "Print the version"},
+		{
+			Use:   "users",
+			Short: "Manage users",
+			Long:  "Manage users in the system",
+			Commands: []*Command[*Context]{
+				{Use: "list", Short: "List users"},
+				{
+					Use:     "create",
+					Short:   "Create a user",
+					Example: "users create -email max@example.com",
+					Flags: []FlagSpec{
+						{Name: "email", Short: "e", Description: "Email of the user", Required: true},
+						{Name: "role", Description: "Role of the user", Default: "member"},
+					},
+				},
+			},
+		},
+	}
+	c := Cli[*Context](&Context{}, cmds)
+
+	t.Run("Root", func(t *testing.T) {
+		data, err := c.RunWithCommand("help")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		list, ok := data.(*DataList)
+		if !ok || len(list.Items) != 2 {
+			t.Errorf("Expected list of root commands, got %v", data)
+		}
+	})
+
+	t.Run("Users", func(t *testing.T) {
+		data, err := c.RunWithCommand("help users")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		v, _ := data.Display(&TextFormatter{})
+		expected := strings.Join([]string{
+			"users",
+			"",
+			"Manage users in the system",
+			"",
+			"Commands:",
+			"  list    List users",
+			"  create  Create a user",
+		}, "\n")
+		if v != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, v)
+		}
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		data, err := c.RunWithCommand("help users create")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		v, _ := data.Display(&TextFormatter{})
+		if !strings.Contains(v, "Example:\n  users create -email max@example.com") {
+			t.Errorf("Expected example, got\n%s", v)
+		}
+		expected := "Flags:\n  -email, -e  Email of the user (required)\n  -role       Role of the user (default: member)"
+		if !strings.HasSuffix(v, expected) {
+			t.Errorf("Expected flags, got\n%s", v)
+		}
+
+		v, _ = data.Display(&JSONFormatter{})
+		if !strings.Contains(v, `"flags":[{"name":"email","short":"e","description":"Email of the user","required":true},{"name":"role","description":"Role of the user","default":"member"}]`) {
+			t.Errorf("Expected flags in JSON output, got %s", v)
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := c.RunWithCommand("help users bogus")
+		if err == nil || err.Error() != "unknown help topic: users bogus" {
+			t.Errorf("Expected unknown help topic error, got %v", err)
+		}
+	})
+}
+
+func TestRenderExamples(t *testing.T) {
+	cmd := &Command[*Context]{
+		Use:     "export",
+		Example: "export -json",
+		Examples: []string{
+			"export -format csv > users.csv",
+			"export -since 2024-01-01 | jq .",
+		},
+	}
+
+	expected := "$ export -json\n$ export -format csv > users.csv\n$ export -since 2024-01-01 | jq ."
+	if v := cmd.RenderExamples(); v != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, v)
+	}
+
+	c := Cli[*Context](&Context{}, []*Command[*Context]{cmd})
+	data, err := c.RunWithCommand("help export")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	v, _ := data.Display(&TextFormatter{})
+	expected = strings.Join([]string{
+		"export",
+		"",
+		"Examples:",
+		"  $ export -json",
+		"  $ export -format csv > users.csv",
+		"  $ export -since 2024-01-01 | jq .",
+	}, "\n")
+	if v != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, v)
+	}
+
+	if v := (&Command[*Context]{}).RenderExamples(); v != "" {
+		t.Errorf("Expected no examples, got %q", v)
+	}
+}
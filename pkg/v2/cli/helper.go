@@ -2,22 +2,32 @@ package cli
 
 import (
 	"bufio"
+	"encoding"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func Input(model interface{}, args []string) error {
-	argMap := ParseArgs(args)
-	return InputFromModel(model, argMap)
+	argMap := ParseMultiArgs(args)
+	return InputFromModelMulti(model, argMap)
 }
 
+// ParseArgs parses flags of the form "-name value" into a map. Flags without
+// a value are set to "". Parsing stops at a standalone "--", all following
+// args are positional.
 func ParseArgs(args []string) map[string]string {
 	argMap := make(map[string]string)
 
 	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			break
+		}
 		if strings.HasPrefix(args[i], "-") {
 			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
 				argMap[strings.TrimPrefix(args[i], "-")] = args[i+1]
@@ -31,8 +41,233 @@ func ParseArgs(args []string) map[string]string {
 	return argMap
 }
 
+// ParseShortFlags parses the args like ParseArgs and additionally expands
+// clusters of single character boolean flags, e.g. "-xvf" into "-x -v -f".
+// boolFlags lists the known boolean flags, e.g. "xvf". Each expanded flag is
+// set to "true" and does not consume the following argument. Clusters
+// containing unknown characters are parsed like ParseArgs does.
+func ParseShortFlags(args []string, boolFlags string) map[string]string {
+	argMap := make(map[string]string)
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			break
+		}
+		if !strings.HasPrefix(args[i], "-") {
+			continue
+		}
+		if cluster, ok := shortFlagCluster(args[i], boolFlags); ok {
+			for _, r := range cluster {
+				argMap[string(r)] = "true"
+			}
+			continue
+		}
+		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			argMap[strings.TrimPrefix(args[i], "-")] = args[i+1]
+			i++
+		} else {
+			argMap[strings.TrimPrefix(args[i], "-")] = ""
+		}
+	}
+
+	return argMap
+}
+
+// shortFlagCluster returns the characters of arg if it is a cluster of known
+// boolean flags.
+func shortFlagCluster(arg string, boolFlags string) (string, bool) {
+	if strings.HasPrefix(arg, "--") || len(arg) < 2 {
+		return "", false
+	}
+	cluster := arg[1:]
+	for _, r := range cluster {
+		if !strings.ContainsRune(boolFlags, r) {
+			return "", false
+		}
+	}
+	return cluster, true
+}
+
+// NormalizeArgs returns a copy of the parsed args with surrounding whitespace
+// and one pair of matching single or double quotes removed from each value,
+// e.g. ` "Max" ` becomes `Max`.
+func NormalizeArgs(args map[string]string) map[string]string {
+	normalized := make(map[string]string, len(args))
+	for k, v := range args {
+		v = strings.TrimSpace(v)
+		if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+			v = strings.TrimSpace(v[1 : len(v)-1])
+		}
+		normalized[k] = v
+	}
+	return normalized
+}
+
+// ParseBool parses a boolean flag value. It accepts true/false, 1/0, yes/no
+// and on/off case-insensitively and treats the empty value of a bare flag as
+// true. The second result reports whether the value was recognized.
+func ParseBool(s string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "true", "1", "yes", "on":
+		return true, true
+	case "false", "0", "no", "off":
+		return false, true
+	}
+	return false, false
+}
+
+// MergeArgs merges the maps in order, values of later maps override values of
+// earlier maps, e.g. MergeArgs(config, env, flags). Empty values are skipped so
+// they don't override a default.
+func MergeArgs(maps ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, m := range maps {
+		for k, v := range m {
+			if v == "" {
+				continue
+			}
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// ParseMultiArgs parses the args like ParseArgs but keeps all values of flags
+// that are passed multiple times, e.g. "-label env=prod -label team=core".
+func ParseMultiArgs(args []string) map[string][]string {
+	argMap := make(map[string][]string)
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			break
+		}
+		if strings.HasPrefix(args[i], "-") {
+			key := strings.TrimPrefix(args[i], "-")
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				argMap[key] = append(argMap[key], args[i+1])
+				i++
+			} else {
+				argMap[key] = append(argMap[key], "")
+			}
+		}
+	}
+
+	return argMap
+}
+
+// InputFromModel binds the args to the fields of the model that are tagged
+// with `validate:"required"`, prompting for missing values on stdin.
+//
+// Optional fields tagged with `prompt:"true"` are bound and prompted for as
+// well, but blank input leaves them at their zero value.
+//
+// Fields tagged with `secretfile:"true"` treat the given value as a path and
+// bind the trimmed contents of that file instead, e.g. for secrets mounted in
+// CI.
+//
+// Fields whose type implements encoding.TextUnmarshaler are parsed with
+// UnmarshalText, so domain types such as enums can reject invalid values.
+// Bool fields are parsed with ParseBool, time.Duration fields with
+// time.ParseDuration, e.g. "30s" or "1h30m".
+//
+// The args key of a field is the value of its arg tag, e.g. `arg:"api-key"`,
+// or the lowercased field name if it has none. Use CliRoot.InputFromModel to
+// derive it with the ArgName of the root instead.
 func InputFromModel(model interface{}, args map[string]string) error {
-	reader := bufio.NewReader(os.Stdin)
+	return InputFromModelMulti(model, multiArgs(args))
+}
+
+func multiArgs(args map[string]string) map[string][]string {
+	multi := make(map[string][]string, len(args))
+	for k, v := range args {
+		multi[k] = []string{v}
+	}
+	return multi
+}
+
+// InputFromModelMulti works like InputFromModel but accepts multiple values per
+// flag. Fields of type map[string]string consume all values, each split on the
+// first "=" into key and value. Other fields use the last value.
+//
+// After binding, the model is validated with ValidateStruct. A required field
+// passes if its arg is given, even with a zero value such as "-count 0", or if
+// a non-blank value is entered at the prompt.
+func InputFromModelMulti(model interface{}, args map[string][]string) error {
+	return inputFromModel(model, args, strings.ToLower)
+}
+
+// inputFromModel binds and validates the model, deriving the args keys of
+// fields without an arg tag with argName.
+func inputFromModel(model interface{}, args map[string][]string, argName func(string) string) error {
+	provided := map[string]bool{}
+	if err := bindModel(model, args, argName, provided); err != nil {
+		return err
+	}
+	return validateStruct(model, provided)
+}
+
+// InputFromModel works like the InputFromModel function but derives the args
+// keys with the ArgName of the root, e.g. cmd.Root().InputFromModel(&input,
+// ParseArgs(args)) within a command.
+func (c *CliRoot[T]) InputFromModel(model interface{}, args map[string]string) error {
+	return c.InputFromModelMulti(model, multiArgs(args))
+}
+
+// InputFromModelMulti works like the InputFromModelMulti function but derives
+// the args keys with the ArgName of the root.
+func (c *CliRoot[T]) InputFromModelMulti(model interface{}, args map[string][]string) error {
+	argName := c.ArgName
+	if argName == nil {
+		argName = strings.ToLower
+	}
+	return inputFromModel(model, args, argName)
+}
+
+// WithArgName sets the ArgName of the root, e.g. WithArgName[T](KebabCase).
+func WithArgName[T any](argName func(name string) string) Option[T] {
+	return func(c *CliRoot[T]) {
+		c.ArgName = argName
+	}
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// unmarshalText sets the field using its UnmarshalText method, so domain types
+// can parse and validate the input themselves. It reports false if the field
+// does not implement encoding.TextUnmarshaler.
+func unmarshalText(field reflect.Value, input string) (bool, error) {
+	if field.Kind() == reflect.Ptr && field.Type().Implements(textUnmarshalerType) {
+		v := reflect.New(field.Type().Elem())
+		if err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(input)); err != nil {
+			return true, err
+		}
+		field.Set(v)
+		return true, nil
+	}
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return true, field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(input))
+	}
+	return false, nil
+}
+
+// fieldArg returns the args key and the prompt label of a field.
+func fieldArg(field reflect.StructField, argName func(string) string) (string, string) {
+	if name := field.Tag.Get("arg"); name != "" {
+		return name, name
+	}
+	return argName(field.Name), field.Name
+}
+
+// inputReader is the source of interactive input. It is a variable so tests
+// can provide input.
+var inputReader io.Reader = os.Stdin
+
+// bindModel sets the fields of the model from the args or the prompt and
+// records the names of the fields given a value in provided.
+func bindModel(model interface{}, args map[string][]string, argName func(string) string, provided map[string]bool) error {
+	reader := bufio.NewReader(inputReader)
 	val := reflect.ValueOf(model).Elem()
 
 	for i := 0; i < val.NumField(); i++ {
@@ -40,23 +275,77 @@ func InputFromModel(model interface{}, args map[string]string) error {
 		fieldType := val.Type().Field(i)
 
 		tag := fieldType.Tag.Get("validate")
-		if !strings.Contains(tag, "required") {
+		required := strings.Contains(tag, "required")
+		if !required && fieldType.Tag.Get("prompt") != "true" {
 			continue
 		}
 
-		input, ok := args[strings.ToLower(fieldType.Name)]
-		if !ok {
-			fmt.Printf("Enter %s: ", fieldType.Name)
+		name, label := fieldArg(fieldType, argName)
+		values := args[name]
+		given := len(values) > 0
+		if !given {
+			fmt.Printf("Enter %s: ", label)
 			inputValue, err := reader.ReadString('\n')
 			if err != nil {
 				return fmt.Errorf("error reading input: %w", err)
 			}
-			input = strings.TrimSpace(inputValue)
+			values = []string{strings.TrimSpace(inputValue)}
+		}
+		input := values[len(values)-1]
+		if !required && input == "" {
+			continue
+		}
+		provided[fieldType.Name] = given || input != ""
+
+		if fieldType.Tag.Get("secretfile") == "true" {
+			content, err := os.ReadFile(input)
+			if err != nil {
+				return fmt.Errorf("error reading secret file for %s: %w", fieldType.Name, err)
+			}
+			input = strings.TrimSpace(string(content))
+			values = []string{input}
+		}
+
+		if ok, err := unmarshalText(field, input); ok {
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", fieldType.Name, err)
+			}
+			continue
 		}
 
 		switch field.Kind() {
+		case reflect.Map:
+			if field.Type().Key().Kind() != reflect.String || field.Type().Elem().Kind() != reflect.String {
+				fmt.Printf("Unsupported type: %s\n", field.Type())
+				return fmt.Errorf("unsupported type: %s", field.Type())
+			}
+			m := reflect.MakeMap(field.Type())
+			for _, value := range values {
+				k, v, found := strings.Cut(value, "=")
+				if !found {
+					return fmt.Errorf("error parsing key=value pair: %s", value)
+				}
+				m.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(v))
+			}
+			field.Set(m)
 		case reflect.String:
 			field.SetString(input)
+		case reflect.Bool:
+			b, ok := ParseBool(input)
+			if !ok {
+				return fmt.Errorf("error parsing bool: invalid value %q", input)
+			}
+			field.SetBool(b)
+		case reflect.Int64:
+			if field.Type() != durationType {
+				fmt.Printf("Unsupported type: %s\n", field.Kind())
+				return fmt.Errorf("unsupported type: %s", field.Kind())
+			}
+			d, err := time.ParseDuration(input)
+			if err != nil {
+				return fmt.Errorf("error parsing duration: %w", err)
+			}
+			field.SetInt(int64(d))
 		case reflect.Int:
 			i, err := strconv.Atoi(input)
 			if err != nil {
@@ -73,6 +362,12 @@ func InputFromModel(model interface{}, args map[string]string) error {
 					return fmt.Errorf("error parsing int: %w", err)
 				}
 				field.Set(reflect.ValueOf(&i))
+			} else if field.Type().Elem() == durationType {
+				d, err := time.ParseDuration(input)
+				if err != nil {
+					return fmt.Errorf("error parsing duration: %w", err)
+				}
+				field.Set(reflect.ValueOf(&d))
 			} else {
 				fmt.Printf("Unsupported type: %s\n", field.Kind())
 				return fmt.Errorf("unsupported type: %s", field.Kind())
@@ -85,3 +380,43 @@ func InputFromModel(model interface{}, args map[string]string) error {
 
 	return nil
 }
+
+// maxSelectAttempts is the number of invalid inputs Select accepts before it
+// returns an error.
+const maxSelectAttempts = 3
+
+// ErrInvalidSelection is returned by Select if no valid option was chosen.
+var ErrInvalidSelection = errors.New("invalid selection")
+
+// Select prints the numbered options and asks the user to choose one. It
+// returns the index of the chosen option. Invalid input is prompted again up
+// to three times.
+func Select(prompt string, options []string) (int, error) {
+	return SelectFrom(os.Stdin, prompt, options)
+}
+
+// SelectFrom works like Select but reads the input from r.
+func SelectFrom(r io.Reader, prompt string, options []string) (int, error) {
+	if len(options) == 0 {
+		return 0, fmt.Errorf("no options to select from")
+	}
+	reader := bufio.NewReader(r)
+
+	for i, option := range options {
+		fmt.Printf("%d) %s\n", i+1, option)
+	}
+	for attempt := 0; attempt < maxSelectAttempts; attempt++ {
+		fmt.Printf("%s [1-%d]: ", prompt, len(options))
+		inputValue, err := reader.ReadString('\n')
+		if err != nil && inputValue == "" {
+			return 0, fmt.Errorf("error reading input: %w", err)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(inputValue))
+		if err != nil || n < 1 || n > len(options) {
+			fmt.Printf("Please enter a number between 1 and %d\n", len(options))
+			continue
+		}
+		return n - 1, nil
+	}
+	return 0, ErrInvalidSelection
+}
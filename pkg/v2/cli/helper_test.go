@@ -1,7 +1,13 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseArgs(t *testing.T) {
@@ -47,6 +53,28 @@ func TestInputFromModelWithArgs(t *testing.T) {
 		}
 	})
 
+	t.Run("ZeroValues", func(t *testing.T) {
+		type Options struct {
+			Count int    `validate:"required"`
+			Force bool   `validate:"required"`
+			Name  string `validate:"required"`
+		}
+
+		o := Options{}
+		err := InputFromModel(&o, map[string]string{"count": "0", "force": "false", "name": ""})
+		if err != nil {
+			t.Errorf("Expected explicit zero values to pass, got %v", err)
+		}
+
+		reader := inputReader
+		defer func() { inputReader = reader }()
+		inputReader = strings.NewReader("\n")
+		err = InputFromModel(&o, map[string]string{"count": "0", "force": "false"})
+		if err == nil || err.Error() != "validation failed: Name is required" {
+			t.Errorf("Expected a blank prompt to fail, got %v", err)
+		}
+	})
+
 	t.Run("Int", func(t *testing.T) {
 		type A struct {
 			A int  `validate:"required"`
@@ -108,3 +136,427 @@ func TestInputFromModelWithArgs(t *testing.T) {
 		}
 	})
 }
+
+func TestParseMultiArgs(t *testing.T) {
+	args := []string{"-label", "env=prod", "-label", "team=core", "-name", "test"}
+
+	m := ParseMultiArgs(args)
+	if len(m["label"]) != 2 {
+		t.Errorf("Expected 2 labels, got %d", len(m["label"]))
+	}
+	if m["name"][0] != "test" {
+		t.Errorf("Expected name to be test, got %s", m["name"])
+	}
+}
+
+func TestInputMap(t *testing.T) {
+	t.Run("Labels", func(t *testing.T) {
+		type Model struct {
+			Name  string            `validate:"required"`
+			Label map[string]string `validate:"required"`
+		}
+
+		m := Model{}
+		err := Input(&m, []string{"-name", "test", "-label", "env=prod", "-label", "team=core=x", "-label", "env=dev"})
+		if err != nil {
+			t.Fatalf("Error parsing input: %v", err)
+		}
+		if len(m.Label) != 2 {
+			t.Errorf("Expected 2 labels, got %d", len(m.Label))
+		}
+		if m.Label["env"] != "dev" {
+			t.Errorf("Expected env to be dev, got %s", m.Label["env"])
+		}
+		if m.Label["team"] != "core=x" {
+			t.Errorf("Expected team to be core=x, got %s", m.Label["team"])
+		}
+	})
+
+	t.Run("Invalid pair", func(t *testing.T) {
+		type Model struct {
+			Label map[string]string `validate:"required"`
+		}
+
+		m := Model{}
+		err := Input(&m, []string{"-label", "env"})
+		if err == nil {
+			t.Errorf("Expected error parsing key=value pair")
+		}
+	})
+}
+
+func TestSelect(t *testing.T) {
+	options := []string{"red", "green", "blue"}
+
+	t.Run("Valid", func(t *testing.T) {
+		i, err := SelectFrom(strings.NewReader("2\n"), "Color", options)
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+		if i != 1 {
+			t.Errorf("Expected 1, got %d", i)
+		}
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		i, err := SelectFrom(strings.NewReader("blue\n4\n3\n"), "Color", options)
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+		if i != 2 {
+			t.Errorf("Expected 2, got %d", i)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := SelectFrom(strings.NewReader("0\nx\n9\n1\n"), "Color", options)
+		if !errors.Is(err, ErrInvalidSelection) {
+			t.Errorf("Expected ErrInvalidSelection, got %v", err)
+		}
+	})
+}
+
+func TestNormalizeArgs(t *testing.T) {
+	args := ParseArgs([]string{"-name", `"Max"`, "-city", "  'Berlin' ", "-note", ` "it's" `, "-raw", "plain", "-half", `"open`})
+
+	if args["name"] != `"Max"` {
+		t.Errorf("Expected ParseArgs to keep raw values, got %s", args["name"])
+	}
+
+	m := NormalizeArgs(args)
+	expected := map[string]string{
+		"name": "Max",
+		"city": "Berlin",
+		"note": "it's",
+		"raw":  "plain",
+		"half": `"open`,
+	}
+	for k, v := range expected {
+		if m[k] != v {
+			t.Errorf("Expected %s to be %q, got %q", k, v, m[k])
+		}
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	tests := []struct {
+		input string
+		value bool
+		ok    bool
+	}{
+		{"", true, true},
+		{"true", true, true},
+		{"TRUE", true, true},
+		{"1", true, true},
+		{"yes", true, true},
+		{"Yes", true, true},
+		{"on", true, true},
+		{"false", false, true},
+		{"False", false, true},
+		{"0", false, true},
+		{"no", false, true},
+		{"NO", false, true},
+		{"off", false, true},
+		{"maybe", false, false},
+		{"2", false, false},
+		{"y", false, false},
+	}
+	for _, tt := range tests {
+		value, ok := ParseBool(tt.input)
+		if value != tt.value || ok != tt.ok {
+			t.Errorf("ParseBool(%q) = %v, %v, want %v, %v", tt.input, value, ok, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestInputFromModelBool(t *testing.T) {
+	type model struct {
+		Force bool `prompt:"true"`
+	}
+	t.Run("Value", func(t *testing.T) {
+		m := model{}
+		if err := InputFromModel(&m, map[string]string{"force": "yes"}); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		if !m.Force {
+			t.Errorf("Expected Force to be true")
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		m := model{}
+		err := InputFromModel(&m, map[string]string{"force": "maybe"})
+		if err == nil || err.Error() != `error parsing bool: invalid value "maybe"` {
+			t.Errorf("Expected parse error, got %v", err)
+		}
+	})
+}
+
+func TestInputFromModelDuration(t *testing.T) {
+	type model struct {
+		Timeout  time.Duration  `validate:"required"`
+		Interval *time.Duration `prompt:"true"`
+	}
+	for _, tt := range []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"30s", 30 * time.Second},
+		{"1h30m", 90 * time.Minute},
+	} {
+		t.Run(tt.input, func(t *testing.T) {
+			m := model{}
+			if err := InputFromModel(&m, map[string]string{"timeout": tt.input, "interval": tt.input}); err != nil {
+				t.Fatalf("Expected no error, got %s", err)
+			}
+			if m.Timeout != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, m.Timeout)
+			}
+			if m.Interval == nil || *m.Interval != tt.expected {
+				t.Errorf("Expected interval %s, got %v", tt.expected, m.Interval)
+			}
+		})
+	}
+	t.Run("Invalid", func(t *testing.T) {
+		m := model{}
+		err := InputFromModel(&m, map[string]string{"timeout": "soon"})
+		if err == nil || err.Error() != `error parsing duration: time: invalid duration "soon"` {
+			t.Errorf("Expected parse error, got %v", err)
+		}
+	})
+	t.Run("Pointer invalid", func(t *testing.T) {
+		m := model{}
+		err := InputFromModel(&m, map[string]string{"timeout": "1s", "interval": "soon"})
+		if err == nil || !strings.HasPrefix(err.Error(), "error parsing duration:") {
+			t.Errorf("Expected parse error, got %v", err)
+		}
+	})
+}
+
+func TestInputFromModelArgName(t *testing.T) {
+	type Config struct {
+		APIKey    string `validate:"required" arg:"api-key"`
+		AccountID string `validate:"required"`
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		config := Config{}
+		err := InputFromModel(&config, map[string]string{"api-key": "secret", "accountid": "42"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		if config.APIKey != "secret" || config.AccountID != "42" {
+			t.Errorf("Unexpected config: %+v", config)
+		}
+	})
+
+	t.Run("KebabCase", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, nil, WithArgName[*Context](KebabCase))
+
+		config := Config{}
+		err := c.InputFromModel(&config, map[string]string{"api-key": "secret", "account-id": "42"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		if config.APIKey != "secret" || config.AccountID != "42" {
+			t.Errorf("Unexpected config: %+v", config)
+		}
+
+		// other roots keep the default
+		config = Config{}
+		err = Cli[*Context](&Context{}, nil).InputFromModel(&config, map[string]string{"api-key": "secret", "accountid": "42"})
+		if err != nil || config.AccountID != "42" {
+			t.Errorf("Expected the default name, got %+v, %v", config, err)
+		}
+	})
+}
+
+func TestMergeArgs(t *testing.T) {
+	config := map[string]string{"host": "localhost", "port": "5432", "user": "admin"}
+	env := map[string]string{"host": "db.internal", "user": ""}
+	flags := map[string]string{"port": "6432", "verbose": "true"}
+
+	m := MergeArgs(config, env, flags)
+	expected := map[string]string{
+		"host":    "db.internal",
+		"port":    "6432",
+		"user":    "admin",
+		"verbose": "true",
+	}
+	if len(m) != len(expected) {
+		t.Errorf("Expected %d args, got %v", len(expected), m)
+	}
+	for k, v := range expected {
+		if m[k] != v {
+			t.Errorf("Expected %s to be %q, got %q", k, v, m[k])
+		}
+	}
+
+	if m := MergeArgs(); len(m) != 0 {
+		t.Errorf("Expected empty map, got %v", m)
+	}
+}
+
+func TestParseShortFlags(t *testing.T) {
+	t.Run("Cluster", func(t *testing.T) {
+		m := ParseShortFlags([]string{"-xvf", "archive.tar", "-name", "test"}, "xvf")
+		for _, k := range []string{"x", "v", "f"} {
+			if m[k] != "true" {
+				t.Errorf("Expected %s to be true, got %q", k, m[k])
+			}
+		}
+		if m["name"] != "test" {
+			t.Errorf("Expected name to be test, got %s", m["name"])
+		}
+		if len(m) != 4 {
+			t.Errorf("Expected 4 arguments, got %v", m)
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		m := ParseShortFlags([]string{"-xq", "value", "-v"}, "xvf")
+		if m["xq"] != "value" {
+			t.Errorf("Expected xq to be left as-is, got %v", m)
+		}
+		if _, ok := m["x"]; ok {
+			t.Errorf("Expected x not to be expanded, got %v", m)
+		}
+		if m["v"] != "true" {
+			t.Errorf("Expected v to be true, got %q", m["v"])
+		}
+	})
+}
+
+func TestParseArgsSeparator(t *testing.T) {
+	args := []string{"-name", "test", "--", "-weird.txt", "-json"}
+
+	if m := ParseArgs(args); len(m) != 1 || m["name"] != "test" {
+		t.Errorf("Expected only name to be parsed, got %v", m)
+	}
+	if m := ParseMultiArgs(args); len(m) != 1 {
+		t.Errorf("Expected only name to be parsed, got %v", m)
+	}
+	if m := ParseShortFlags([]string{"-x", "--", "-x"}, "x"); len(m) != 1 {
+		t.Errorf("Expected only x to be parsed, got %v", m)
+	}
+}
+
+func TestInputSecretFile(t *testing.T) {
+	type Model struct {
+		Password string `validate:"required" secretfile:"true"`
+	}
+
+	t.Run("Read", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "password")
+		if err := os.WriteFile(path, []byte("s3cr3t\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		m := Model{}
+		if err := InputFromModel(&m, map[string]string{"password": path}); err != nil {
+			t.Fatalf("Error parsing input: %v", err)
+		}
+		if m.Password != "s3cr3t" {
+			t.Errorf("Expected s3cr3t, got %q", m.Password)
+		}
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		m := Model{}
+		err := InputFromModel(&m, map[string]string{"password": filepath.Join(t.TempDir(), "missing")})
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected os.ErrNotExist, got %v", err)
+		}
+	})
+}
+
+func TestInputPrompt(t *testing.T) {
+	type Model struct {
+		Name     string `validate:"required"`
+		Nickname string `prompt:"true"`
+		Age      *int   `prompt:"true"`
+		Other    string
+	}
+
+	reader := inputReader
+	defer func() { inputReader = reader }()
+
+	t.Run("Blank", func(t *testing.T) {
+		inputReader = strings.NewReader("\n\n")
+		m := Model{}
+		if err := InputFromModel(&m, map[string]string{"name": "test"}); err != nil {
+			t.Fatalf("Error parsing input: %v", err)
+		}
+		if m.Nickname != "" || m.Age != nil {
+			t.Errorf("Expected optional fields to stay empty, got %+v", m)
+		}
+	})
+
+	t.Run("Filled", func(t *testing.T) {
+		inputReader = strings.NewReader("Maxi\n20\n")
+		m := Model{}
+		if err := InputFromModel(&m, map[string]string{"name": "test"}); err != nil {
+			t.Fatalf("Error parsing input: %v", err)
+		}
+		if m.Nickname != "Maxi" {
+			t.Errorf("Expected Maxi, got %q", m.Nickname)
+		}
+		if m.Age == nil || *m.Age != 20 {
+			t.Errorf("Expected age 20, got %v", m.Age)
+		}
+	})
+
+	t.Run("Args", func(t *testing.T) {
+		inputReader = strings.NewReader("")
+		m := Model{}
+		if err := InputFromModel(&m, map[string]string{"name": "test", "nickname": "Maxi", "age": "20", "other": "x"}); err != nil {
+			t.Fatalf("Error parsing input: %v", err)
+		}
+		if m.Nickname != "Maxi" || m.Other != "" {
+			t.Errorf("Unexpected model: %+v", m)
+		}
+	})
+}
+
+type Role string
+
+func (r *Role) UnmarshalText(text []byte) error {
+	switch Role(text) {
+	case "admin", "member":
+		*r = Role(text)
+		return nil
+	}
+	return fmt.Errorf("invalid role %q", text)
+}
+
+func TestInputTextUnmarshaler(t *testing.T) {
+	type Model struct {
+		Role     Role  `validate:"required"`
+		Fallback *Role `validate:"required"`
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		m := Model{}
+		if err := InputFromModel(&m, map[string]string{"role": "admin", "fallback": "member"}); err != nil {
+			t.Fatalf("Error parsing input: %v", err)
+		}
+		if m.Role != "admin" {
+			t.Errorf("Expected admin, got %s", m.Role)
+		}
+		if m.Fallback == nil || *m.Fallback != "member" {
+			t.Errorf("Expected member, got %v", m.Fallback)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		m := Model{}
+		err := InputFromModel(&m, map[string]string{"role": "owner", "fallback": "member"})
+		if err == nil || !strings.Contains(err.Error(), `invalid role "owner"`) {
+			t.Errorf("Expected invalid role error, got %v", err)
+		}
+
+		err = InputFromModel(&m, map[string]string{"role": "admin", "fallback": "guest"})
+		if err == nil || m.Fallback != nil {
+			t.Errorf("Expected invalid role error and no fallback, got %v, %v", err, m.Fallback)
+		}
+	})
+}
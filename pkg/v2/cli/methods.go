@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/Talk-Point/go-toolkit/pkg/v2/formatter"
+)
+
+// CommandDocer can be implemented by receivers of CommandsFromStruct to
+// document the commands. CommandDoc returns the Short description by method
+// name.
+type CommandDocer interface {
+	CommandDoc() map[string]string
+}
+
+// CommandsFromStruct returns a command for each exported method of the
+// receiver with the signature func(args []string, ctx T) (Data, error). The
+// Use of a command is the kebab-cased method name, e.g. "ListUsers" becomes
+// "list-users". Commands are sorted by method name.
+func CommandsFromStruct[T any](receiver interface{}) []*Command[T] {
+	var doc map[string]string
+	if d, ok := receiver.(CommandDocer); ok {
+		doc = d.CommandDoc()
+	}
+
+	v := reflect.ValueOf(receiver)
+	commands := []*Command[T]{}
+	for i := 0; i < v.NumMethod(); i++ {
+		run, ok := v.Method(i).Interface().(func(args []string, ctx T) (Data, error))
+		if !ok {
+			continue
+		}
+		name := v.Type().Method(i).Name
+		commands = append(commands, &Command[T]{
+			Use:   KebabCase(name),
+			Short: doc[name],
+			Run: func(cmd *Command[T], args []string, ctx T) (Data, error) {
+				return run(args, ctx)
+			},
+		})
+	}
+	return commands
+}
+
+// KebabCase converts a camelCase or PascalCase name to kebab-case, e.g.
+// "api-key" for APIKey.
+func KebabCase(name string) string {
+	return strings.ReplaceAll(strings.ToLower(formatter.TitleCaseFromCamel(name)), " ", "-")
+}
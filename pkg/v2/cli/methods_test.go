@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"testing"
+)
+
+type userCommands struct {
+	users []string
+}
+
+func (u *userCommands) ListUsers(args []string, ctx *Context) (Data, error) {
+	return &DataMessage{Message: "users: " + u.users[0]}, nil
+}
+
+func (u *userCommands) Create(args []string, ctx *Context) (Data, error) {
+	return &DataMessage{Message: "created " + args[0]}, nil
+}
+
+func (u *userCommands) Helper(s string) string {
+	return s
+}
+
+func (u *userCommands) CommandDoc() map[string]string {
+	return map[string]string{"ListUsers": "List all users"}
+}
+
+func TestCommandsFromStruct(t *testing.T) {
+	cmds := CommandsFromStruct[*Context](&userCommands{users: []string{"max"}})
+	if len(cmds) != 2 {
+		t.Fatalf("Expected 2 commands, got %d", len(cmds))
+	}
+	if cmds[0].Use != "create" || cmds[1].Use != "list-users" {
+		t.Errorf("Unexpected commands: %s, %s", cmds[0].Use, cmds[1].Use)
+	}
+	if cmds[1].Short != "List all users" || cmds[0].Short != "" {
+		t.Errorf("Unexpected docs: %q, %q", cmds[0].Short, cmds[1].Short)
+	}
+
+	c := Cli[*Context](&Context{}, cmds)
+	data, err := c.RunWithCommand("list-users")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v, _ := data.Display(&TextFormatter{}); v != "users: max" {
+		t.Errorf("Unexpected output: %q", v)
+	}
+	data, _ = c.RunWithCommand("create erika")
+	if v, _ := data.Display(&TextFormatter{}); v != "created erika" {
+		t.Errorf("Unexpected output: %q", v)
+	}
+
+	if v := KebabCase("ExportCSVFile"); v != "export-csv-file" {
+		t.Errorf("Expected export-csv-file, got %s", v)
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// NDJSONFormatter implements Formatter to output JSON Lines, e.g. for
+// processing the output line by line with jq. A DataList is rendered as one
+// JSON object per item, other data as a single line of JSON.
+type NDJSONFormatter struct{}
+
+func (f *NDJSONFormatter) Format(data interface{}) (string, error) {
+	list, ok := data.(*DataList)
+	if !ok {
+		return f.line(data)
+	}
+	lines := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		var v interface{} = item
+		if list.OrderedJSON {
+			v = newOrderedMap(item, list.Columns)
+		}
+		line, err := f.line(v)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (f *NDJSONFormatter) line(data interface{}) (string, error) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("error formatting %T as ndjson: %w", data, err)
+	}
+	return string(jsonData), nil
+}
+
+func (f *NDJSONFormatter) Type() string {
+	return "ndjson"
+}
+
+// isJSON reports whether the formatter outputs JSON, so data types render
+// their structured representation.
+func isJSON(formatter Formatter) bool {
+	t := formatter.Type()
+	return t == "json" || t == "ndjson"
+}
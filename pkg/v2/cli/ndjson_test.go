@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNDJSONFormatter(t *testing.T) {
+	t.Run("List", func(t *testing.T) {
+		data := &DataList{
+			Title: "Users",
+			Items: []map[string]string{
+				{"id": "1", "team": "core"},
+				{"id": "2", "team": "web"},
+			},
+			GroupBy:   "team",
+			ShowIndex: true,
+		}
+		v, err := data.Display(&NDJSONFormatter{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		lines := strings.Split(v, "\n")
+		if len(lines) != 2 || lines[0] != `{"id":"1","team":"core"}` || lines[1] != `{"id":"2","team":"web"}` {
+			t.Errorf("Expected one object per line, got %q", v)
+		}
+	})
+
+	t.Run("Scalar", func(t *testing.T) {
+		v, _ := (&DataDetails{Title: "User", Item: map[string]string{"id": "1"}}).Display(&NDJSONFormatter{})
+		if v != `{"title":"User","item":{"id":"1"}}` {
+			t.Errorf("Unexpected output: %q", v)
+		}
+	})
+
+	t.Run("Flag", func(t *testing.T) {
+		cmds := []*Command[*Context]{
+			{
+				Use: "list",
+				Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+					return &DataList{Items: []map[string]string{{"id": "1"}, {"id": "2"}}}, nil
+				},
+			},
+		}
+		var out bytes.Buffer
+		c := Cli[*Context](&Context{}, cmds)
+		c.Writer = &out
+		c.RunArgs([]string{"list", "-ndjson"})
+		if out.String() != "{\"id\":\"1\"}\n{\"id\":\"2\"}\n" {
+			t.Errorf("Unexpected output: %q", out.String())
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// orderedMap is a map marshaled to JSON with its keys in the given order.
+type orderedMap struct {
+	keys []string
+	m    map[string]string
+}
+
+// newOrderedMap returns m with the declared keys first, in the given order,
+// followed by the remaining keys sorted. Declared keys missing from m are
+// skipped.
+func newOrderedMap(m map[string]string, declared []string) orderedMap {
+	keys := make([]string, 0, len(m))
+	seen := make(map[string]bool, len(declared))
+	for _, k := range declared {
+		if _, ok := m[k]; ok && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	rest := make([]string, 0, len(m)-len(keys))
+	for k := range m {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return orderedMap{keys: append(keys, rest...), m: m}
+}
+
+func (o orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.m[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonItems returns the items for the JSON output, ordered by the Columns if
+// OrderedJSON is set.
+func (d *DataList) jsonItems(items []map[string]string) interface{} {
+	if !d.OrderedJSON {
+		return items
+	}
+	ordered := make([]orderedMap, len(items))
+	for i, item := range items {
+		ordered[i] = newOrderedMap(item, d.Columns)
+	}
+	return ordered
+}
+
+// orderedList is the JSON representation of a DataList with OrderedJSON.
+type orderedList struct {
+	Title    string      `json:"title"`
+	Items    interface{} `json:"items"`
+	Total    int         `json:"total,omitempty"`
+	Page     int         `json:"page,omitempty"`
+	PageSize int         `json:"page_size,omitempty"`
+}
+
+// orderedDetails is the JSON representation of a DataDetails with Keys.
+type orderedDetails struct {
+	Title string     `json:"title"`
+	Item  orderedMap `json:"item"`
+}
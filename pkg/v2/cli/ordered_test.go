@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOrderedJSON(t *testing.T) {
+	list := func() *DataList {
+		return &DataList{
+			Title: "Users",
+			Items: []map[string]string{
+				{"name": "Alice", "id": "1", "role": "admin", "email": "a@example.com"},
+				{"name": "Bob", "id": "2", "email": "b@example.com"},
+			},
+			Columns:     []string{"name", "id", "role"},
+			OrderedJSON: true,
+		}
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		expected := `{"title":"Users","items":[{"name":"Alice","id":"1","role":"admin","email":"a@example.com"},{"name":"Bob","id":"2","email":"b@example.com"}]}`
+		for i := 0; i < 20; i++ {
+			v, err := list().Display(&JSONFormatter{})
+			if err != nil {
+				t.Fatalf("Expected no error, got %s", err)
+			}
+			if v != expected {
+				t.Fatalf("Unexpected output in run %d: %s", i, v)
+			}
+		}
+	})
+
+	t.Run("WriteJSON", func(t *testing.T) {
+		d := list()
+		var b bytes.Buffer
+		if err := d.WriteJSON(&b); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		expected, _ := d.Display(&JSONFormatter{})
+		if b.String() != expected {
+			t.Errorf("Expected %s, got %s", expected, b.String())
+		}
+	})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		v, err := list().Display(&NDJSONFormatter{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		expected := `{"name":"Alice","id":"1","role":"admin","email":"a@example.com"}` + "\n" + `{"name":"Bob","id":"2","email":"b@example.com"}`
+		if v != expected {
+			t.Errorf("Unexpected output: %s", v)
+		}
+	})
+
+	t.Run("Grouped", func(t *testing.T) {
+		d := list()
+		d.GroupBy = "role"
+		v, err := d.Display(&JSONFormatter{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		expected := `{"title":"Users","groups":{"admin":[{"name":"Alice","id":"1","role":"admin","email":"a@example.com"}],"ungrouped":[{"name":"Bob","id":"2","email":"b@example.com"}]}}`
+		if v != expected {
+			t.Errorf("Unexpected output: %s", v)
+		}
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		d := list()
+		d.OrderedJSON = false
+		v, err := d.Display(&JSONFormatter{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		expected := `{"title":"Users","items":[{"email":"a@example.com","id":"1","name":"Alice","role":"admin"},{"email":"b@example.com","id":"2","name":"Bob"}]}`
+		if v != expected {
+			t.Errorf("Unexpected output: %s", v)
+		}
+	})
+
+	t.Run("Details", func(t *testing.T) {
+		d := &DataDetails{
+			Title: "User",
+			Item:  map[string]string{"name": "Alice", "id": "1", "email": "a@example.com"},
+			Keys:  []string{"name", "id", "missing"},
+		}
+		v, err := d.Display(&JSONFormatter{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		expected := `{"title":"User","item":{"name":"Alice","id":"1","email":"a@example.com"}}`
+		if v != expected {
+			t.Errorf("Unexpected output: %s", v)
+		}
+	})
+}
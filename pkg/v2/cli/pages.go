@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxPages is the number of pages CollectPages fetches at most.
+var MaxPages = 1000
+
+// ErrTooManyPages is returned by CollectPages if the pages don't end within
+// MaxPages or a cursor is repeated.
+var ErrTooManyPages = errors.New("too many pages")
+
+// CollectPages calls fetch with the cursor of each page, starting with "",
+// until the returned cursor is empty, and returns the items of all pages, e.g.
+// for DataListFromStructs. The first error of fetch is returned with the items
+// collected so far.
+func CollectPages[T any](fetch func(cursor string) (items []T, nextCursor string, err error)) ([]T, error) {
+	all := []T{}
+	seen := map[string]bool{}
+	cursor := ""
+	for page := 0; ; page++ {
+		if page >= MaxPages {
+			return all, fmt.Errorf("%w: stopped after %d pages", ErrTooManyPages, MaxPages)
+		}
+		items, next, err := fetch(cursor)
+		all = append(all, items...)
+		if err != nil {
+			return all, err
+		}
+		if next == "" {
+			return all, nil
+		}
+		if seen[next] {
+			return all, fmt.Errorf("%w: cursor %q repeated", ErrTooManyPages, next)
+		}
+		seen[next] = true
+		cursor = next
+	}
+}
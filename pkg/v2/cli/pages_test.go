@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCollectPages(t *testing.T) {
+	pages := map[string]struct {
+		items []int
+		next  string
+	}{
+		"":   {[]int{1, 2}, "p2"},
+		"p2": {[]int{3, 4}, "p3"},
+		"p3": {[]int{5}, ""},
+	}
+
+	t.Run("Pages", func(t *testing.T) {
+		cursors := []string{}
+		items, err := CollectPages(func(cursor string) ([]int, string, error) {
+			cursors = append(cursors, cursor)
+			page := pages[cursor]
+			return page.items, page.next, nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		if !reflect.DeepEqual(items, []int{1, 2, 3, 4, 5}) {
+			t.Errorf("Unexpected items: %v", items)
+		}
+		if !reflect.DeepEqual(cursors, []string{"", "p2", "p3"}) {
+			t.Errorf("Unexpected cursors: %q", cursors)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		failed := errors.New("failed")
+		items, err := CollectPages(func(cursor string) ([]int, string, error) {
+			if cursor == "p2" {
+				return nil, "", failed
+			}
+			page := pages[cursor]
+			return page.items, page.next, nil
+		})
+		if err != failed {
+			t.Errorf("Expected failed error, got %v", err)
+		}
+		if !reflect.DeepEqual(items, []int{1, 2}) {
+			t.Errorf("Unexpected items: %v", items)
+		}
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		calls := 0
+		_, err := CollectPages(func(cursor string) ([]int, string, error) {
+			calls++
+			if cursor == "b" {
+				return []int{2}, "a", nil
+			}
+			return []int{1}, "b", nil
+		})
+		if !errors.Is(err, ErrTooManyPages) {
+			t.Errorf("Expected ErrTooManyPages, got %v", err)
+		}
+		if calls != 3 {
+			t.Errorf("Expected 3 calls, got %d", calls)
+		}
+	})
+
+	t.Run("MaxPages", func(t *testing.T) {
+		maxPages := MaxPages
+		defer func() { MaxPages = maxPages }()
+		MaxPages = 5
+
+		calls := 0
+		items, err := CollectPages(func(cursor string) ([]int, string, error) {
+			calls++
+			return []int{calls}, cursor + "x", nil
+		})
+		if !errors.Is(err, ErrTooManyPages) {
+			t.Errorf("Expected ErrTooManyPages, got %v", err)
+		}
+		if calls != 5 || len(items) != 5 {
+			t.Errorf("Expected 5 pages, got %d calls and %d items", calls, len(items))
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemStatus maps the Code of a DataError to the HTTP status of its problem
+// details, see ProblemJSON. Errors without a code or with a code not in the
+// map have the status 500. Applications can add their own codes.
+var ProblemStatus = map[string]int{
+	CodeCommandNotFound:  http.StatusNotFound,
+	CodeAmbiguousCommand: http.StatusBadRequest,
+	CodeUnknownFlag:      http.StatusBadRequest,
+	CodeTimeout:          http.StatusGatewayTimeout,
+	CodeValidationFailed: http.StatusUnprocessableEntity,
+}
+
+// Problem holds the problem details of an error as defined by RFC 7807.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// Problem returns the problem details of the error. The type is the code of
+// the error, or "about:blank" if it has none, and the title the text of its
+// HTTP status.
+func (d *DataError) Problem() Problem {
+	problem := Problem{
+		Type:   "about:blank",
+		Status: http.StatusInternalServerError,
+		Detail: d.Message,
+	}
+	if d.Code != "" {
+		problem.Type = d.Code
+		if status, ok := ProblemStatus[d.Code]; ok {
+			problem.Status = status
+		}
+	}
+	problem.Title = http.StatusText(problem.Status)
+	return problem
+}
+
+// ProblemJSON renders the error as problem details, to be served with the
+// content type "application/problem+json".
+func (d *DataError) ProblemJSON() (string, error) {
+	v, err := json.Marshal(d.Problem())
+	if err != nil {
+		return "", err
+	}
+	return string(v), nil
+}
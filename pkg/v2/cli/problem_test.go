@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestDataErrorProblemJSON(t *testing.T) {
+	t.Run("WithCode", func(t *testing.T) {
+		v, err := commandNotFound("deploy").ProblemJSON()
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		expected := `{"type":"command_not_found","title":"Not Found","status":404,"detail":"command deploy not found"}`
+		if v != expected {
+			t.Errorf("Expected %s, got %s", expected, v)
+		}
+	})
+
+	t.Run("WithoutCode", func(t *testing.T) {
+		v, err := (&DataError{Message: "boom"}).ProblemJSON()
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		expected := `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"boom"}`
+		if v != expected {
+			t.Errorf("Expected %s, got %s", expected, v)
+		}
+	})
+
+	t.Run("UnknownCode", func(t *testing.T) {
+		problem := (&DataError{Message: "quota exceeded", Code: "quota_exceeded"}).Problem()
+		if problem.Type != "quota_exceeded" || problem.Status != 500 {
+			t.Errorf("Unexpected problem: %+v", problem)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	formattersLock sync.RWMutex
+	formatters     = map[string]Formatter{
+		"json":   &JSONFormatter{},
+		"ndjson": &NDJSONFormatter{},
+		"text":   &TextFormatter{},
+		"table":  &TableFormatter{},
+		"csv":    &CSVFormatter{},
+		"tsv":    &CSVFormatter{Delimiter: '\t'},
+	}
+)
+
+// RegisterFormatter registers a formatter under the name, so it is selected
+// with "-output name". Registering a built-in name such as "json" replaces the
+// built-in formatter, also for its shorthand flag, e.g. -json.
+func RegisterFormatter(name string, f Formatter) {
+	formattersLock.Lock()
+	defer formattersLock.Unlock()
+	formatters[name] = f
+}
+
+// LookupFormatter returns the formatter registered under the name.
+func LookupFormatter(name string) (Formatter, bool) {
+	formattersLock.RLock()
+	defer formattersLock.RUnlock()
+	f, ok := formatters[name]
+	return f, ok
+}
+
+// FormatterNames returns the sorted names of the registered formatters.
+func FormatterNames() []string {
+	formattersLock.RLock()
+	defer formattersLock.RUnlock()
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatterFor returns the registered formatter or an error listing the valid
+// names.
+func formatterFor(name string) (Formatter, error) {
+	f, ok := LookupFormatter(name)
+	if !ok {
+		return nil, &DataError{Message: fmt.Sprintf("unknown output format %q, valid formats: %s", name, strings.Join(FormatterNames(), ", "))}
+	}
+	return f, nil
+}
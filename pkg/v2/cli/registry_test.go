@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+type reportFormatter struct{}
+
+func (r *reportFormatter) Format(data interface{}) (string, error) {
+	return fmt.Sprintf("REPORT: %v", data), nil
+}
+
+func (r *reportFormatter) Type() string {
+	return "report"
+}
+
+func TestRegisterFormatter(t *testing.T) {
+	RegisterFormatter("report", &reportFormatter{})
+	defer func() {
+		formattersLock.Lock()
+		delete(formatters, "report")
+		formattersLock.Unlock()
+	}()
+
+	cmds := []*Command[*Context]{
+		{
+			Use: "status",
+			Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+				return &DataDetails{Title: "Status", Item: map[string]string{"ok": "yes"}}, nil
+			},
+		},
+	}
+
+	for _, args := range [][]string{
+		{"-output", "report", "status"},
+		{"status", "--output=report"},
+	} {
+		c := Cli[*Context](&Context{}, cmds)
+		out := &bytes.Buffer{}
+		c.Writer = out
+		if code := c.RunArgs(args); code != 0 {
+			t.Errorf("Expected exit code 0 for %v, got %d", args, code)
+		}
+		if out.String() != "REPORT: Status\nok: yes\n" {
+			t.Errorf("Unexpected output for %v: %q", args, out.String())
+		}
+	}
+
+	t.Run("Unknown", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		_, err := c.runCommand(c.Commands, []string{"status", "-output", "xml"})
+		if err == nil || err.Error() != `unknown output format "xml", valid formats: csv, json, ndjson, report, table, text, tsv` {
+			t.Errorf("Expected unknown format error, got %v", err)
+		}
+	})
+
+	t.Run("Builtin", func(t *testing.T) {
+		c := Cli[*Context](&Context{}, cmds)
+		if _, err := c.runCommand(c.Commands, []string{"status", "-output", "ndjson"}); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		if c.Formatter.Type() != "ndjson" {
+			t.Errorf("Expected ndjson formatter, got %s", c.Formatter.Type())
+		}
+	})
+	t.Run("CommandOutputFlag", func(t *testing.T) {
+		var got map[string]string
+		var format string
+		cmds := []*Command[*Context]{
+			{
+				Use:        "report",
+				KnownFlags: []string{"output"},
+				Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+					got = ParseArgs(args)
+					format = cmd.Root().Formatter.Type()
+					return nil, nil
+				},
+			},
+		}
+		c := Cli[*Context](&Context{}, cmds)
+		c.RunArgs([]string{"report", "-output", "report.txt"})
+		if len(got) != 1 || got["output"] != "report.txt" || format != "text" {
+			t.Errorf("Expected the command to receive -output, got %v with %s output", got, format)
+		}
+
+		// before the command name, -output still selects the formatter
+		c.RunArgs([]string{"-output", "json", "report", "-output", "report.txt"})
+		if got["output"] != "report.txt" || format != "json" {
+			t.Errorf("Expected json output and -output for the command, got %v with %s output", got, format)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Status values of a DataResult.
+const (
+	StatusSuccess = "success"
+	StatusPartial = "partial"
+	StatusFailure = "failure"
+)
+
+// DataResult is a uniform result envelope, e.g. for CI jobs reading the JSON
+// output. Data holds the payload of the command and may itself be a Data,
+// which is then rendered with the same formatter in text output.
+type DataResult struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+	Errors []string    `json:"errors,omitempty"`
+}
+
+func (d *DataResult) Display(formatter Formatter) (string, error) {
+	if isJSON(formatter) {
+		return formatter.Format(d)
+	}
+
+	a := []string{"Status: " + d.Status}
+	if d.Data != nil {
+		var v string
+		if data, ok := d.Data.(Data); ok {
+			var err error
+			v, err = data.Display(formatter)
+			if err != nil {
+				return "", err
+			}
+		} else {
+			v = fmt.Sprintf("%v", d.Data)
+		}
+		a = append(a, v)
+	}
+	if len(d.Errors) > 0 {
+		a = append(a, fmt.Sprintf("Errors (%d):", len(d.Errors)))
+		for _, e := range d.Errors {
+			a = append(a, "  - "+e)
+		}
+	}
+	return strings.Join(a, "\n"), nil
+}
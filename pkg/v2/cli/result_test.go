@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestDataResult(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		data := &DataResult{
+			Status: StatusSuccess,
+			Data:   &DataMessage{Message: "Imported 2 users"},
+		}
+
+		v, err := data.Display(&JSONFormatter{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if v != `{"status":"success","data":{"message":"Imported 2 users"}}` {
+			t.Errorf("Unexpected JSON output: %s", v)
+		}
+
+		v, _ = data.Display(&TextFormatter{})
+		if v != "Status: success\nImported 2 users" {
+			t.Errorf("Unexpected text output: %q", v)
+		}
+	})
+
+	t.Run("Partial failure", func(t *testing.T) {
+		data := &DataResult{
+			Status: StatusPartial,
+			Data:   map[string]int{"imported": 1},
+			Errors: []string{"user 2: invalid email"},
+		}
+
+		v, _ := data.Display(&JSONFormatter{})
+		if v != `{"status":"partial","data":{"imported":1},"errors":["user 2: invalid email"]}` {
+			t.Errorf("Unexpected JSON output: %s", v)
+		}
+
+		v, _ = data.Display(&TextFormatter{})
+		if v != "Status: partial\nmap[imported:1]\nErrors (1):\n  - user 2: invalid email" {
+			t.Errorf("Unexpected text output: %q", v)
+		}
+	})
+}
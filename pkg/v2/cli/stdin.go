@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bufio"
+	"io"
+	"os"
+)
+
+// MaxLineLength is the maximum length of a line read by ForEachLine. Longer
+// lines abort the reading with bufio.ErrTooLong.
+var MaxLineLength = bufio.MaxScanTokenSize
+
+// stdinIsTerminal is a variable so tests can simulate a terminal.
+var stdinIsTerminal = func() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ForEachStdinLine calls fn for each line piped to stdin, see ForEachLine. It
+// does nothing if stdin is a terminal, so it never blocks waiting for the
+// user.
+func ForEachStdinLine(fn func(line string) error) error {
+	if stdinIsTerminal() {
+		return nil
+	}
+	return ForEachLine(os.Stdin, fn)
+}
+
+// ForEachLine calls fn for each line read from r without the line ending. It
+// stops at the first error returned by fn and returns it.
+func ForEachLine(r io.Reader, fn func(line string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(MaxLineLength, bufio.MaxScanTokenSize)), MaxLineLength)
+	for scanner.Scan() {
+		if err := fn(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestForEachStdinLine(t *testing.T) {
+	stdin := os.Stdin
+	isTerminal := stdinIsTerminal
+	defer func() {
+		os.Stdin = stdin
+		stdinIsTerminal = isTerminal
+	}()
+
+	pipe := func(t *testing.T, input string) {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			w.WriteString(input)
+			w.Close()
+		}()
+		os.Stdin = r
+	}
+
+	t.Run("Piped", func(t *testing.T) {
+		stdinIsTerminal = isTerminal
+		pipe(t, "a,1\nb,2\r\n\nc,3")
+		var lines []string
+		err := ForEachStdinLine(func(line string) error {
+			lines = append(lines, line)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		if !reflect.DeepEqual(lines, []string{"a,1", "b,2", "", "c,3"}) {
+			t.Errorf("Unexpected lines: %q", lines)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		stdinIsTerminal = isTerminal
+		pipe(t, "a\nb\nc\n")
+		stop := errors.New("stop")
+		var lines []string
+		err := ForEachStdinLine(func(line string) error {
+			lines = append(lines, line)
+			if line == "b" {
+				return stop
+			}
+			return nil
+		})
+		if err != stop {
+			t.Errorf("Expected stop error, got %v", err)
+		}
+		if !reflect.DeepEqual(lines, []string{"a", "b"}) {
+			t.Errorf("Unexpected lines: %q", lines)
+		}
+	})
+
+	t.Run("Terminal", func(t *testing.T) {
+		stdinIsTerminal = func() bool { return true }
+		called := false
+		err := ForEachStdinLine(func(line string) error {
+			called = true
+			return nil
+		})
+		if err != nil || called {
+			t.Errorf("Expected no-op, got called=%v err=%v", called, err)
+		}
+	})
+}
+
+func TestForEachLineMaxLength(t *testing.T) {
+	maxLength := MaxLineLength
+	defer func() { MaxLineLength = maxLength }()
+	MaxLineLength = 8
+
+	err := ForEachLine(strings.NewReader("short\nmuch too long\n"), func(line string) error {
+		return nil
+	})
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("Expected ErrTooLong, got %v", err)
+	}
+}
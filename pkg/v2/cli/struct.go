@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DataDetailsFromStruct builds a DataDetails from the exported fields of a struct.
+// Keys are taken from the json tag of each field, falling back to the field name.
+// Fields tagged with `json:"-"` are skipped, and fields tagged with omitempty are
+// skipped when they hold their zero value. Nested structs are flattened using
+// dotted keys, e.g. "address.city".
+func DataDetailsFromStruct(title string, v interface{}) *DataDetails {
+	item := map[string]string{}
+	flattenStruct(reflect.ValueOf(v), "", item)
+	return &DataDetails{
+		Title: title,
+		Item:  item,
+	}
+}
+
+// DataListFromStructs builds a DataList from a slice of structs or pointers to
+// structs. Each item is converted using the same rules as DataDetailsFromStruct.
+// Nil pointers are skipped.
+func DataListFromStructs[T any](title string, items []T) *DataList {
+	data := &DataList{
+		Title: title,
+		Items: []map[string]string{},
+	}
+	for _, v := range items {
+		val := reflect.ValueOf(v)
+		if val.Kind() == reflect.Ptr && val.IsNil() {
+			continue
+		}
+		item := map[string]string{}
+		flattenStruct(val, "", item)
+		data.Items = append(data.Items, item)
+	}
+	return data
+}
+
+func flattenStruct(val reflect.Value, prefix string, item map[string]string) {
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Field(i)
+		fieldType := val.Type().Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, skip := jsonFieldName(fieldType)
+		if skip {
+			continue
+		}
+		if omitEmpty && field.IsZero() {
+			continue
+		}
+		key := prefix + name
+
+		if isNestedStruct(field) {
+			flattenStruct(field, key+".", item)
+			continue
+		}
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				item[key] = ""
+				continue
+			}
+			field = field.Elem()
+		}
+		item[key] = fmt.Sprint(field.Interface())
+	}
+}
+
+// jsonFieldName returns the key of a struct field based on its json tag.
+func jsonFieldName(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
+
+// isNestedStruct reports whether the value is a struct (or pointer to one) that
+// should be flattened. Structs implementing fmt.Stringer, such as time.Time, are
+// treated as plain values.
+func isNestedStruct(val reflect.Value) bool {
+	if _, ok := val.Interface().(fmt.Stringer); ok {
+		return false
+	}
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return false
+		}
+		if _, ok := val.Elem().Interface().(fmt.Stringer); ok {
+			return false
+		}
+		return val.Elem().Kind() == reflect.Struct
+	}
+	return val.Kind() == reflect.Struct
+}
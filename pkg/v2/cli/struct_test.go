@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestDataDetailsFromStruct(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type User struct {
+		Id       string `json:"id"`
+		Email    string `json:"email,omitempty"`
+		Name     string
+		Age      int     `json:"age"`
+		Password string  `json:"-"`
+		Address  Address `json:"address"`
+		secret   string
+	}
+
+	data := DataDetailsFromStruct("User", &User{
+		Id:       "1",
+		Name:     "Max",
+		Age:      20,
+		Password: "secret",
+		Address:  Address{City: "Berlin"},
+		secret:   "secret",
+	})
+
+	if data.Title != "User" {
+		t.Errorf("Expected title User, got %s", data.Title)
+	}
+	expected := map[string]string{
+		"id":           "1",
+		"Name":         "Max",
+		"age":          "20",
+		"address.city": "Berlin",
+	}
+	if len(data.Item) != len(expected) {
+		t.Errorf("Expected %d keys, got %d: %v", len(expected), len(data.Item), data.Item)
+	}
+	for k, v := range expected {
+		if data.Item[k] != v {
+			t.Errorf("Expected %s to be %s, got %s", k, v, data.Item[k])
+		}
+	}
+}
+
+func TestDataListFromStructs(t *testing.T) {
+	type User struct {
+		Id     string `json:"id"`
+		Email  string `json:"email"`
+		secret string
+	}
+
+	t.Run("Values", func(t *testing.T) {
+		data := DataListFromStructs("Users", []User{
+			{Id: "1", Email: "a@example.com", secret: "a"},
+			{Id: "2", Email: "b@example.com", secret: "b"},
+		})
+		if data.Title != "Users" {
+			t.Errorf("Expected title Users, got %s", data.Title)
+		}
+		if len(data.Items) != 2 {
+			t.Fatalf("Expected 2 items, got %d", len(data.Items))
+		}
+		if data.Items[1]["id"] != "2" || data.Items[1]["email"] != "b@example.com" {
+			t.Errorf("Unexpected item: %v", data.Items[1])
+		}
+		if _, ok := data.Items[0]["secret"]; ok {
+			t.Errorf("Unexported field should be skipped")
+		}
+	})
+
+	t.Run("Pointers", func(t *testing.T) {
+		data := DataListFromStructs("Users", []*User{
+			{Id: "1", Email: "a@example.com"},
+			nil,
+		})
+		if len(data.Items) != 1 {
+			t.Fatalf("Expected 1 item, got %d", len(data.Items))
+		}
+		if data.Items[0]["id"] != "1" {
+			t.Errorf("Expected id 1, got %s", data.Items[0]["id"])
+		}
+	})
+}
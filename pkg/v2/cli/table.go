@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Talk-Point/go-toolkit/pkg/v2/formatter"
+)
+
+// TableFormatter implements Formatter to output DataList and DataDetails as
+// aligned tables. Other data is formatted like the TextFormatter.
+//
+// Column widths are computed from the display width of the values, so
+// multibyte characters such as umlauts and wide characters such as emoji or
+// CJK characters are aligned correctly.
+//
+// Headers are humanized, e.g. "created_at" is rendered as "Created At". Set
+// RawHeaders to render the keys unchanged.
+//
+// If WrapWidth is greater than zero, cells wider than WrapWidth are wrapped at
+// word boundaries onto multiple lines. Set it to WrapAuto to wrap at the width
+// of the terminal.
+type TableFormatter struct {
+	RawHeaders bool
+	WrapWidth  int
+}
+
+func (t *TableFormatter) Format(data interface{}) (string, error) {
+	switch d := data.(type) {
+	case *DataList:
+		columns := d.Columns
+		if len(columns) == 0 {
+			columns = listColumns(d.Items)
+		}
+		rows := [][]string{}
+		for _, item := range d.Items {
+			row := []string{}
+			for _, column := range columns {
+				row = append(row, t.wrap(item[column]))
+			}
+			rows = append(rows, row)
+		}
+		table := titled(d.Title, renderTable(t.headers(columns), rows))
+		if footer := d.footer(); footer != "" {
+			table += "\n" + footer
+		}
+		return table, nil
+	case *DataDetails:
+		keys := sortedKeys(d.Item)
+		rows := [][]string{}
+		for _, k := range keys {
+			rows = append(rows, []string{k, t.wrap(d.Item[k])})
+		}
+		return titled(d.Title, renderTable(t.headers([]string{"key", "value"}), rows)), nil
+	}
+	return t.wrap(fmt.Sprintf("%v", data)), nil
+}
+
+func (t *TableFormatter) wrap(s string) string {
+	return wrapText(s, resolveWidth(t.WrapWidth))
+}
+
+func (t *TableFormatter) Type() string {
+	return "table"
+}
+
+func (t *TableFormatter) headers(columns []string) []string {
+	if t.RawHeaders {
+		return columns
+	}
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = humanizeKey(column)
+	}
+	return headers
+}
+
+// humanizeKey converts a snake_case, kebab-case or camelCase key to a title
+// cased label.
+func humanizeKey(key string) string {
+	if strings.ContainsAny(key, "_- ") {
+		return formatter.TitleCaseFromSnake(key)
+	}
+	return formatter.TitleCaseFromCamel(key)
+}
+
+// listColumns returns the sorted union of all keys of the items.
+func listColumns(items []map[string]string) []string {
+	seen := map[string]bool{}
+	columns := []string{}
+	for _, item := range items {
+		for k := range item {
+			if !seen[k] {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func titled(title string, table string) string {
+	if title == "" {
+		return table
+	}
+	return title + "\n" + table
+}
+
+// renderTable renders a header, a separator row and the rows with each column
+// padded to its widest value. Cells containing newlines span multiple lines.
+func renderTable(header []string, rows [][]string) string {
+	lineRows := [][]string{}
+	for _, row := range rows {
+		lineRows = append(lineRows, cellLines(row)...)
+	}
+	rows = lineRows
+
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = displayWidth(h)
+	}
+	for _, row := range rows {
+		for i, v := range row {
+			if w := displayWidth(v); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	separator := make([]string, len(header))
+	for i, w := range widths {
+		separator[i] = strings.Repeat("-", w)
+	}
+
+	lines := []string{renderRow(header, widths), renderRow(separator, widths)}
+	for _, row := range rows {
+		lines = append(lines, renderRow(row, widths))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cellLines splits a row with multi-line cells into one row per line.
+func cellLines(row []string) [][]string {
+	cells := make([][]string, len(row))
+	height := 1
+	for i, v := range row {
+		cells[i] = strings.Split(v, "\n")
+		if len(cells[i]) > height {
+			height = len(cells[i])
+		}
+	}
+	lines := make([][]string, height)
+	for l := range lines {
+		lines[l] = make([]string, len(row))
+		for i := range row {
+			if l < len(cells[i]) {
+				lines[l][i] = cells[i][l]
+			}
+		}
+	}
+	return lines
+}
+
+func renderRow(row []string, widths []int) string {
+	var b strings.Builder
+	for i, v := range row {
+		b.WriteString(v)
+		if i < len(row)-1 {
+			b.WriteString(strings.Repeat(" ", widths[i]-displayWidth(v)+2))
+		}
+	}
+	return b.String()
+}
+
+// displayWidth returns the number of terminal cells needed to display s.
+// Combining marks and zero width characters take no space, East Asian wide
+// characters and emoji take two cells and all other runes take one.
+func displayWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Mn, r), unicode.Is(unicode.Me, r), r == 0x200B, r == 0x200D, r >= 0xFE00 && r <= 0xFE0F:
+		case isWide(r):
+			w += 2
+		default:
+			w++
+		}
+	}
+	return w
+}
+
+func isWide(r rune) bool {
+	switch {
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0x303E,
+		r >= 0x3041 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F,
+		r >= 0x1F900 && r <= 0x1F9FF,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return true
+	}
+	return false
+}
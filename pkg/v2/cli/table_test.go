@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableFormatter(t *testing.T) {
+	t.Run("DataList", func(t *testing.T) {
+		data := &DataList{
+			Title: "Users",
+			Items: []map[string]string{
+				{"id": "1", "name": "Max"},
+				{"id": "22", "name": "Jürgen Müller"},
+			},
+		}
+		v, err := data.Display(&TableFormatter{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := strings.Join([]string{
+			"Users",
+			"ID  Name",
+			"--  -------------",
+			"1   Max",
+			"22  Jürgen Müller",
+		}, "\n")
+		if v != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, v)
+		}
+	})
+
+	t.Run("Wide characters", func(t *testing.T) {
+		data := &DataList{
+			Items: []map[string]string{
+				{"name": "🎉🎉", "id": "1"},
+				{"name": "日本", "id": "2"},
+				{"name": "abc", "id": "3"},
+			},
+			Columns: []string{"name", "id"},
+		}
+		v, _ := data.Display(&TableFormatter{})
+		lines := strings.Split(v, "\n")
+		if lines[1] != "----  --" {
+			t.Errorf("Expected separator to match visual width, got %q", lines[1])
+		}
+		for _, line := range lines[2:] {
+			if displayWidth(line) != displayWidth(lines[1])-1 {
+				t.Errorf("Expected line %q to be aligned", line)
+			}
+		}
+	})
+
+	t.Run("DataDetails", func(t *testing.T) {
+		data := &DataDetails{
+			Title: "User",
+			Item:  map[string]string{"name": "Zoë", "id": "1"},
+		}
+		v, _ := data.Display(&TableFormatter{})
+		expected := "User\nKey   Value\n----  -----\nid    1\nname  Zoë"
+		if v != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, v)
+		}
+	})
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := map[string]int{
+		"abc":    3,
+		"Müller": 6,
+		"🎉":      2,
+		"日本語":    6,
+		"é":     1,
+	}
+	for s, expected := range tests {
+		if w := displayWidth(s); w != expected {
+			t.Errorf("Expected width of %q to be %d, got %d", s, expected, w)
+		}
+	}
+}
+
+func TestTableFormatterHeaders(t *testing.T) {
+	data := &DataList{
+		Items:   []map[string]string{{"created_at": "today", "userId": "1"}},
+		Columns: []string{"created_at", "userId"},
+	}
+
+	v, _ := data.Display(&TableFormatter{})
+	if header := strings.Split(v, "\n")[0]; header != "Created At  User ID" {
+		t.Errorf("Expected humanized headers, got %q", header)
+	}
+
+	v, _ = data.Display(&TableFormatter{RawHeaders: true})
+	if header := strings.Split(v, "\n")[0]; header != "created_at  userId" {
+		t.Errorf("Expected raw headers, got %q", header)
+	}
+
+	v, _ = data.Display(&JSONFormatter{})
+	if !strings.Contains(v, `"created_at":"today"`) {
+		t.Errorf("Expected raw keys in JSON, got %s", v)
+	}
+}
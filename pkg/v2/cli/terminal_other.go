@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package cli
+
+import (
+	"os"
+)
+
+// windowWidth returns 0 as the size of the terminal is not queried on this
+// platform, so the width falls back to the COLUMNS environment variable.
+func windowWidth(f *os.File) int {
+	return 0
+}
@@ -0,0 +1,20 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// windowWidth returns the number of columns of the terminal f refers to, or 0
+// if f is not a terminal.
+func windowWidth(f *os.File) int {
+	var size struct{ rows, cols, x, y uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size)))
+	if errno != 0 {
+		return 0
+	}
+	return int(size.cols)
+}
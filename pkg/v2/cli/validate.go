@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError describes a single failed validation rule.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// ValidationError is returned by ValidateStruct and holds all failed rules.
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	a := []string{}
+	for _, f := range e.Fields {
+		a = append(a, f.Message)
+	}
+	return "validation failed: " + strings.Join(a, "; ")
+}
+
+// CodeValidationFailed is the Code of the DataValidationError.
+const CodeValidationFailed = "validation_failed"
+
+// DataValidationError displays a *ValidationError, listing every failed rule
+// in the text output and the fields in the JSON output.
+type DataValidationError struct {
+	Message string       `json:"error"`
+	Code    string       `json:"code"`
+	Fields  []FieldError `json:"fields"`
+	Err     error        `json:"-"`
+}
+
+func (d *DataValidationError) Error() string {
+	return d.Message
+}
+
+func (d *DataValidationError) Unwrap() error {
+	return d.Err
+}
+
+func (d *DataValidationError) Display(formatter Formatter) (string, error) {
+	if isJSON(formatter) {
+		return formatter.Format(d)
+	}
+	lines := []string{"validation failed:"}
+	for _, f := range d.Fields {
+		lines = append(lines, "  - "+f.Message)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ValidateStruct validates the fields of a struct against the rules of their
+// validate tag. Supported rules are:
+//
+//	required   the field must not be the zero value
+//	min=N      minimum value for numbers, minimum length for strings, slices and maps
+//	max=N      maximum value for numbers, maximum length for strings, slices and maps
+//	oneof=a b  the value must be one of the space separated values
+//	email      the value must be a valid email address
+//
+// Rules other than required are skipped for empty values. All failed rules are
+// returned as a *ValidationError.
+func ValidateStruct(model interface{}) error {
+	return validateStruct(model, nil)
+}
+
+// validateStruct validates like ValidateStruct, but fields named in provided
+// pass the required rule even with their zero value, e.g. for "-count 0".
+func validateStruct(model interface{}, provided map[string]bool) error {
+	val := reflect.ValueOf(model)
+	for val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return fmt.Errorf("unsupported type: %s", val.Kind())
+	}
+
+	errs := &ValidationError{}
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Field(i)
+		fieldType := val.Type().Field(i)
+
+		tag := fieldType.Tag.Get("validate")
+		if tag == "" || !fieldType.IsExported() {
+			continue
+		}
+
+		for _, rule := range strings.Split(tag, ",") {
+			name, param, _ := strings.Cut(rule, "=")
+			if name == "required" && provided[fieldType.Name] {
+				continue
+			}
+			if msg := checkRule(field, name, param); msg != "" {
+				errs.Fields = append(errs.Fields, FieldError{
+					Field:   fieldType.Name,
+					Rule:    name,
+					Message: fieldType.Name + " " + msg,
+				})
+			}
+		}
+	}
+
+	if len(errs.Fields) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// checkRule returns a message describing the failure or "" if the rule passes.
+func checkRule(field reflect.Value, rule string, param string) string {
+	if rule == "required" {
+		if field.IsZero() {
+			return "is required"
+		}
+		return ""
+	}
+
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return ""
+		}
+		field = field.Elem()
+	}
+	if field.IsZero() {
+		return ""
+	}
+
+	switch rule {
+	case "min", "max":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return fmt.Sprintf("has invalid %s rule: %s", rule, param)
+		}
+		n, isLength, ok := fieldMeasure(field)
+		if !ok {
+			return ""
+		}
+		if rule == "min" && n < limit {
+			if isLength {
+				return fmt.Sprintf("must have a length of at least %s", param)
+			}
+			return fmt.Sprintf("must be at least %s", param)
+		}
+		if rule == "max" && n > limit {
+			if isLength {
+				return fmt.Sprintf("must have a length of at most %s", param)
+			}
+			return fmt.Sprintf("must be at most %s", param)
+		}
+	case "oneof":
+		v := fmt.Sprint(field.Interface())
+		for _, option := range strings.Fields(param) {
+			if v == option {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of [%s]", param)
+	case "email":
+		v := fmt.Sprint(field.Interface())
+		addr, err := mail.ParseAddress(v)
+		if err != nil || addr.Address != v {
+			return "must be a valid email address"
+		}
+	}
+	return ""
+}
+
+// fieldMeasure returns the number compared by min and max rules, and whether
+// it is a length.
+func fieldMeasure(field reflect.Value) (float64, bool, bool) {
+	switch field.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(field.String())), true, true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(field.Len()), true, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), false, true
+	}
+	return 0, false, false
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type validateUser struct {
+	Name  string `validate:"required,min=3,max=10"`
+	Email string `validate:"required,email"`
+	Role  string `validate:"oneof=admin user"`
+	Age   int    `validate:"min=18,max=99"`
+	Tags  []string
+}
+
+func TestValidateStruct(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		err := ValidateStruct(&validateUser{
+			Name:  "Max",
+			Email: "max@example.com",
+			Role:  "admin",
+			Age:   30,
+		})
+		if err != nil {
+			t.Errorf("Expected nil, got %s", err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		err := ValidateStruct(&validateUser{
+			Name:  "Maximilian Mustermann",
+			Email: "not-an-email",
+			Role:  "root",
+			Age:   12,
+		})
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected ValidationError, got %v", err)
+		}
+		rules := map[string]string{}
+		for _, f := range validationErr.Fields {
+			rules[f.Field] = f.Rule
+		}
+		expected := map[string]string{"Name": "max", "Email": "email", "Role": "oneof", "Age": "min"}
+		if len(rules) != len(expected) {
+			t.Errorf("Expected %d errors, got %v", len(expected), validationErr.Fields)
+		}
+		for field, rule := range expected {
+			if rules[field] != rule {
+				t.Errorf("Expected %s to fail %s, got %s", field, rule, rules[field])
+			}
+		}
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		err := ValidateStruct(&validateUser{})
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected ValidationError, got %v", err)
+		}
+		if len(validationErr.Fields) != 2 {
+			t.Errorf("Expected 2 errors, got %v", validationErr.Fields)
+		}
+	})
+
+	t.Run("InputFromModel", func(t *testing.T) {
+		type Model struct {
+			Email string `validate:"required,email"`
+		}
+		err := InputFromModel(&Model{}, map[string]string{"email": "invalid"})
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+}
+
+func TestAsData(t *testing.T) {
+	t.Run("ValidationError", func(t *testing.T) {
+		err := fmt.Errorf("create user: %w", ValidateStruct(&validateUser{Email: "max@example.com"}))
+		data, ok := AsData(err).(*DataValidationError)
+		if !ok {
+			t.Fatalf("Expected *DataValidationError, got %T", AsData(err))
+		}
+		if data.Code != CodeValidationFailed || len(data.Fields) != 1 || data.Fields[0].Field != "Name" {
+			t.Errorf("Unexpected data: %+v", data)
+		}
+		v, _ := data.Display(&TextFormatter{})
+		if v != "validation failed:\n  - Name is required" {
+			t.Errorf("Unexpected text output: %q", v)
+		}
+		v, _ = data.Display(&JSONFormatter{})
+		if !strings.Contains(v, `"code":"validation_failed"`) || !strings.Contains(v, `"field":"Name"`) {
+			t.Errorf("Unexpected JSON output: %s", v)
+		}
+	})
+
+	t.Run("GenericError", func(t *testing.T) {
+		err := errors.New("boom")
+		data, ok := AsData(err).(*DataError)
+		if !ok {
+			t.Fatalf("Expected *DataError, got %T", AsData(err))
+		}
+		if data.Message != "boom" || !errors.Is(data, err) {
+			t.Errorf("Unexpected data: %+v", data)
+		}
+	})
+
+	t.Run("RunArgs", func(t *testing.T) {
+		cmds := []*Command[*Context]{
+			{
+				Use: "create",
+				Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+					return nil, ValidateStruct(&validateUser{Email: "max@example.com"})
+				},
+			},
+		}
+		c := Cli[*Context](&Context{}, cmds)
+		out := &bytes.Buffer{}
+		c.ErrWriter = out
+		if code := c.RunArgs([]string{"create"}); code != 1 {
+			t.Errorf("Expected exit code 1, got %d", code)
+		}
+		if out.String() != "validation failed:\n  - Name is required\n" {
+			t.Errorf("Unexpected output: %q", out.String())
+		}
+	})
+}
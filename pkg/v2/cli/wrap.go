@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// WrapAuto can be set as WrapWidth of the TextFormatter and the TableFormatter
+// to wrap at the width of the terminal.
+const WrapAuto = -1
+
+// terminalWidth returns the width of the terminal w writes to, or 0 if it is
+// unknown. The size of the terminal is queried first, the COLUMNS environment
+// variable is the fallback. It is a variable so tests can simulate a terminal.
+var terminalWidth = func(w io.Writer) int {
+	f, ok := w.(*os.File)
+	if !ok || !isTerminal(f) {
+		return 0
+	}
+	if width := windowWidth(f); width > 0 {
+		return width
+	}
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || width <= 0 {
+		return 0
+	}
+	return width
+}
+
+// wrapper is implemented by formatters that wrap text, so data types that
+// render text themselves, such as DataMessage, can be wrapped as well.
+type wrapper interface {
+	wrap(s string) string
+}
+
+// resolveWidth returns the width to wrap at for the given WrapWidth, or 0 if
+// text is not wrapped. WrapAuto wraps at the width of stdout, Run resolves it
+// for its Writer beforehand, see wrapFor.
+func resolveWidth(width int) int {
+	if width == WrapAuto {
+		return terminalWidth(os.Stdout)
+	}
+	if width < 0 {
+		return 0
+	}
+	return width
+}
+
+// wrapFor returns the formatter with WrapAuto resolved to the width of the
+// terminal w writes to. The formatter is copied, as it may be shared by
+// concurrent runs.
+func wrapFor(formatter Formatter, w io.Writer) Formatter {
+	switch f := formatter.(type) {
+	case *TextFormatter:
+		if f.WrapWidth == WrapAuto {
+			resolved := *f
+			resolved.WrapWidth = terminalWidth(w)
+			return &resolved
+		}
+	case *TableFormatter:
+		if f.WrapWidth == WrapAuto {
+			resolved := *f
+			resolved.WrapWidth = terminalWidth(w)
+			return &resolved
+		}
+	}
+	return formatter
+}
+
+// wrapText wraps each line of s at word boundaries so that it fits into width
+// terminal cells. The leading whitespace of a line is kept and repeated on the
+// lines it is wrapped onto. Words wider than width are kept on a line of their
+// own. A width of 0 disables wrapping.
+func wrapText(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if displayWidth(line) <= width {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		indentWidth := displayWidth(indent)
+		wrapped := []string{}
+		current, currentWidth := "", 0
+		for _, word := range strings.Fields(line) {
+			w := displayWidth(word)
+			if current != "" && indentWidth+currentWidth+1+w > width {
+				wrapped = append(wrapped, indent+current)
+				current, currentWidth = "", 0
+			}
+			if current != "" {
+				current += " "
+				currentWidth++
+			}
+			current += word
+			currentWidth += w
+		}
+		lines[i] = strings.Join(append(wrapped, indent+current), "\n")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestWrapWidth(t *testing.T) {
+	t.Run("Message", func(t *testing.T) {
+		data := &DataMessage{Message: "the quick brown fox jumps over the lazy dog"}
+		v, _ := data.Display(&TextFormatter{WrapWidth: 15})
+		if v != "the quick brown\nfox jumps over\nthe lazy dog" {
+			t.Errorf("Unexpected wrapped output: %q", v)
+		}
+
+		v, _ = data.Display(&TextFormatter{})
+		if v != data.Message {
+			t.Errorf("Expected no wrapping by default, got %q", v)
+		}
+	})
+
+	t.Run("Multibyte", func(t *testing.T) {
+		v := wrapText("Grüße aus München, 東京 ist weit", 10)
+		if v != "Grüße aus\nMünchen,\n東京 ist\nweit" {
+			t.Errorf("Unexpected wrapped output: %q", v)
+		}
+	})
+
+	t.Run("Long word", func(t *testing.T) {
+		v := wrapText("see https://example.com/a/very/long/path now", 10)
+		if v != "see\nhttps://example.com/a/very/long/path\nnow" {
+			t.Errorf("Unexpected wrapped output: %q", v)
+		}
+	})
+
+	t.Run("Table", func(t *testing.T) {
+		data := &DataList{
+			Items: []map[string]string{
+				{"id": "1", "note": "first line and more"},
+			},
+		}
+		v, _ := data.Display(&TableFormatter{WrapWidth: 10})
+		expected := "ID  Note\n--  ----------\n1   first line\n    and more"
+		if v != expected {
+			t.Errorf("Unexpected table output:\n%s", v)
+		}
+	})
+
+	t.Run("Auto", func(t *testing.T) {
+		width := terminalWidth
+		defer func() { terminalWidth = width }()
+		terminalWidth = func(w io.Writer) int { return 9 }
+
+		v, _ := (&DataMessage{Message: "hello wide world"}).Display(&TextFormatter{WrapWidth: WrapAuto})
+		if v != "hello\nwide\nworld" {
+			t.Errorf("Unexpected wrapped output: %q", v)
+		}
+	})
+
+	t.Run("Indent", func(t *testing.T) {
+		v := wrapText("  - the quick brown fox\nno indent", 12)
+		if v != "  - the\n  quick\n  brown fox\nno indent" {
+			t.Errorf("Unexpected wrapped output: %q", v)
+		}
+	})
+
+	t.Run("Writer", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		width := terminalWidth
+		defer func() { terminalWidth = width }()
+		terminalWidth = func(w io.Writer) int {
+			if w == out {
+				return 9
+			}
+			return 0
+		}
+
+		cmds := []*Command[*Context]{{Use: "hello", Run: func(cmd *Command[*Context], args []string, ctx *Context) (Data, error) {
+			return &DataMessage{Message: "hello wide world"}, nil
+		}}}
+		formatter := &TextFormatter{WrapWidth: WrapAuto}
+		c := Cli[*Context](&Context{}, cmds)
+		c.Formatter = formatter
+		c.Writer = out
+		c.RunArgs([]string{"hello"})
+		if out.String() != "hello\nwide\nworld\n" {
+			t.Errorf("Expected output wrapped at the width of the writer, got %q", out.String())
+		}
+		if formatter.WrapWidth != WrapAuto {
+			t.Errorf("Expected the formatter to be unchanged, got %d", formatter.WrapWidth)
+		}
+	})
+}
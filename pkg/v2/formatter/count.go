@@ -0,0 +1,40 @@
+package formatter
+
+import (
+	"strconv"
+)
+
+var countSuffixes = []struct {
+	divisor float64
+	suffix  string
+}{
+	{1e3, "k"},
+	{1e6, "M"},
+	{1e9, "B"},
+}
+
+// FormatCount abbreviates a large count with a k, M or B suffix and one decimal.
+// The function takes an int64 like 5234 and returns "5.2k", 1300000 returns "1.3M".
+// Values below 1000 are returned unchanged and exact thousands keep the decimal, so 1000 returns "1.0k".
+// Negative values are prefixed with "-".
+func FormatCount(n int64) string {
+	sign := ""
+	abs := uint64(n)
+	if n < 0 {
+		sign = "-"
+		abs = uint64(-(n + 1)) + 1
+	}
+	if abs < 1000 {
+		return strconv.FormatInt(n, 10)
+	}
+
+	for i, s := range countSuffixes {
+		v := strconv.FormatFloat(float64(abs)/s.divisor, 'f', 1, 64)
+		// 999950 rounds to "1000.0k" and is rendered as "1.0M" instead
+		if f, _ := strconv.ParseFloat(v, 64); f >= 1000 && i < len(countSuffixes)-1 {
+			continue
+		}
+		return sign + v + s.suffix
+	}
+	return ""
+}
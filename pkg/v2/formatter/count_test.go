@@ -0,0 +1,34 @@
+package formatter
+
+import (
+	"testing"
+)
+
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int64
+		expected string
+	}{
+		{"zero", 0, "0"},
+		{"below 1000", 999, "999"},
+		{"thousand", 1000, "1.0k"},
+		{"thousands", 5234, "5.2k"},
+		{"below million", 999_949, "999.9k"},
+		{"rounded to million", 999_950, "1.0M"},
+		{"million", 1_300_000, "1.3M"},
+		{"billion", 1_000_000_000, "1.0B"},
+		{"large billions", 2_500_000_000_000, "2500.0B"},
+		{"negative below 1000", -42, "-42"},
+		{"negative", -5234, "-5.2k"},
+		{"min int64", -9223372036854775808, "-9223372036.9B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatCount(tt.n); got != tt.expected {
+				t.Errorf("FormatCount() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -13,22 +14,67 @@ import (
 // If the number of seconds is less than 86400 (1 day), it returns the number of hours, minutes, and remaining seconds in the format "Xh Ym Zs".
 // If the number of seconds is 86400 or more, it returns the number of days in the format "Xd".
 func TimePeriodHumanReadable(seconds int32) string {
-	if seconds == 0 {
-		return "0s"
-	} else if seconds < 60 {
-		return fmt.Sprintf("%ds", seconds)
+	parts := []string{}
+	for _, c := range periodComponents(int64(seconds)) {
+		parts = append(parts, fmt.Sprintf("%d%s", c.value, c.unit[:1]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// TimePeriodHumanReadableLong converts a time period in seconds to a human readable format with spelled out units.
+// The function takes an int64 representing the number of seconds and returns a string like "2 hours 5 minutes".
+// It uses the same tiers as TimePeriodHumanReadable, but omits zero components, so 3600 returns "1 hour".
+// If the number of seconds is 0, it returns "0 seconds".
+func TimePeriodHumanReadableLong(seconds int64) string {
+	parts := []string{}
+	for _, c := range periodComponents(seconds) {
+		if c.value == 0 {
+			continue
+		}
+		unit := c.unit
+		if c.value != 1 && c.value != -1 {
+			unit += "s"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", c.value, unit))
+	}
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+	return strings.Join(parts, " ")
+}
+
+type periodComponent struct {
+	value int64
+	unit  string
+}
+
+// periodComponents splits the seconds into the components shown by TimePeriodHumanReadable.
+func periodComponents(seconds int64) []periodComponent {
+	if seconds < 60 {
+		return []periodComponent{{seconds, "second"}}
 	} else if seconds < 3600 {
-		minutes := seconds / 60
-		remainingSeconds := seconds % 60
-		return fmt.Sprintf("%dm %ds", minutes, remainingSeconds)
+		return []periodComponent{{seconds / 60, "minute"}, {seconds % 60, "second"}}
 	} else if seconds < 86400 {
-		hours := seconds / 3600
-		remainingMinutes := (seconds % 3600) / 60
-		remainingSeconds := (seconds % 3600) % 60
-		return fmt.Sprintf("%dh %dm %ds", hours, remainingMinutes, remainingSeconds)
-	} else {
-		return fmt.Sprintf("%dd", seconds/86400)
+		return []periodComponent{{seconds / 3600, "hour"}, {(seconds % 3600) / 60, "minute"}, {seconds % 60, "second"}}
+	}
+	return []periodComponent{{seconds / 86400, "day"}}
+}
+
+// FormatClock converts a time period in seconds to a clock string.
+// The function takes an int64 representing the number of seconds and returns a string in the format "HH:MM:SS".
+// Hours are not wrapped at 24, so 90000 seconds returns "25:00:00".
+// If the number of seconds is 0, it returns "00:00:00".
+// If the number of seconds is negative, the result is prefixed with "-".
+func FormatClock(seconds int64) string {
+	sign := ""
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
 	}
+	hours := seconds / 3600
+	minutes := (seconds % 3600) / 60
+	remainingSeconds := seconds % 60
+	return fmt.Sprintf("%s%02d:%02d:%02d", sign, hours, minutes, remainingSeconds)
 }
 
 // TimeAbsoluteFormatter converts a time.Time to a human readable format relative to a reference time.Time.
@@ -36,6 +82,7 @@ func TimePeriodHumanReadable(seconds int32) string {
 // If the date is before the referenceDate, it returns the date in the format "X days ago".
 // If the date is after the referenceDate, it returns the date in the format "X days from now".
 // If the date is the same as the referenceDate, it returns "today".
+// The count is truncated, e.g. 119 seconds are "1 minutes ago", see TimeAbsoluteFormatterRounding.
 func TimeAbsoluteFormatter(date time.Time, referenceDate time.Time) string {
 	duration := referenceDate.Sub(date)
 	switch {
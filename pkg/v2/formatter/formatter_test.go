@@ -48,6 +48,57 @@ func TestTimePeriodHumanReadable(t *testing.T) {
 	}
 }
 
+func TestTimePeriodHumanReadableLong(t *testing.T) {
+	tests := []struct {
+		name     string
+		seconds  int64
+		expected string
+	}{
+		{"zero", 0, "0 seconds"},
+		{"one second", 1, "1 second"},
+		{"seconds", 59, "59 seconds"},
+		{"one hour", 3600, "1 hour"},
+		{"hours and minutes", 7500, "2 hours 5 minutes"},
+		{"all units", 3661, "1 hour 1 minute 1 second"},
+		{"minutes and seconds", 125, "2 minutes 5 seconds"},
+		{"days", 172800, "2 days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := TimePeriodHumanReadableLong(tt.seconds)
+			if actual != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFormatClock(t *testing.T) {
+	tests := []struct {
+		name     string
+		seconds  int64
+		expected string
+	}{
+		{"zero", 0, "00:00:00"},
+		{"seconds", 59, "00:00:59"},
+		{"minutes", 61, "00:01:01"},
+		{"hours", 3723, "01:02:03"},
+		{"one day", 86400, "24:00:00"},
+		{"past 24 hours", 90000, "25:00:00"},
+		{"past 100 hours", 360061, "100:01:01"},
+		{"negative", -3723, "-01:02:03"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatClock(tt.seconds); got != tt.expected {
+				t.Errorf("FormatClock() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestTimeAbsoluteFormatter(t *testing.T) {
 	now := time.Now()
 
@@ -81,3 +132,53 @@ func TestTimeAbsoluteFormatter(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeAbsoluteFormatterRounding(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		duration time.Duration
+		rounding Rounding
+		expected string
+	}{
+		{"89s truncate", 89 * time.Second, Truncate, "1 minutes ago"},
+		{"89s round", 89 * time.Second, Round, "1 minutes ago"},
+		{"89s ceil", 89 * time.Second, Ceil, "2 minutes ago"},
+		{"90s round", 90 * time.Second, Round, "2 minutes ago"},
+		{"91s truncate", 91 * time.Second, Truncate, "1 minutes ago"},
+		{"91s round", 91 * time.Second, Round, "2 minutes ago"},
+		{"91s ceil", 91 * time.Second, Ceil, "2 minutes ago"},
+		{"119s truncate", 119 * time.Second, Truncate, "1 minutes ago"},
+		{"119s round", 119 * time.Second, Round, "2 minutes ago"},
+		{"120s ceil", 120 * time.Second, Ceil, "2 minutes ago"},
+		{"89s from now round", -89 * time.Second, Round, "1 minutes from now"},
+		{"91s from now ceil", -91 * time.Second, Ceil, "2 minutes from now"},
+		{"next unit round", 59*time.Second + 600*time.Millisecond, Round, "1 minutes ago"},
+		{"next unit ceil", 6*24*time.Hour + time.Hour, Ceil, "1 weeks ago"},
+		{"29 days truncate", 29 * 24 * time.Hour, Truncate, "4 weeks ago"},
+		{"29 days round", 29 * 24 * time.Hour, Round, "4 weeks ago"},
+		{"29 days ceil", 29 * 24 * time.Hour, Ceil, "1 months ago"},
+		{"359 days truncate", 359 * 24 * time.Hour, Truncate, "11 months ago"},
+		{"359 days round", 359 * 24 * time.Hour, Round, "1 years ago"},
+		{"359 days ceil", 359 * 24 * time.Hour, Ceil, "1 years ago"},
+		{"360 days truncate", 360 * 24 * time.Hour, Truncate, "0 years ago"},
+		{"360 days round", 360 * 24 * time.Hour, Round, "1 years ago"},
+		{"360 days ceil", 360 * 24 * time.Hour, Ceil, "1 years ago"},
+		{"362 days truncate", 362 * 24 * time.Hour, Truncate, "0 years ago"},
+		{"362 days round", 362 * 24 * time.Hour, Round, "1 years ago"},
+		{"362 days ceil", 362 * 24 * time.Hour, Ceil, "1 years ago"},
+		{"365 days truncate", 365 * 24 * time.Hour, Truncate, "1 years ago"},
+		{"365 days round", 365 * 24 * time.Hour, Round, "1 years ago"},
+		{"365 days ceil", 365 * 24 * time.Hour, Ceil, "1 years ago"},
+		{"now", 0, Ceil, "now"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TimeAbsoluteFormatterRounding(now.Add(-tt.duration), now, tt.rounding); got != tt.expected {
+				t.Errorf("TimeAbsoluteFormatterRounding() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeUnit is a unit used by the relative time formatters.
+type TimeUnit int
+
+const (
+	Second TimeUnit = iota
+	Minute
+	Hour
+	Day
+	Week
+	Month
+	Year
+)
+
+// Locale holds the phrases used by TimeAbsoluteFormatterLocale.
+// Past and Future map each unit to a singular and a plural format string, each taking the count as %d.
+// Format strings without %d, e.g. "an hour ago", are used as is.
+type Locale struct {
+	Now    string
+	Past   map[TimeUnit][2]string
+	Future map[TimeUnit][2]string
+}
+
+var (
+	locales = map[string]Locale{
+		"en": {
+			Now: "now",
+			Past: map[TimeUnit][2]string{
+				Second: {"%d seconds ago", "%d seconds ago"},
+				Minute: {"%d minutes ago", "%d minutes ago"},
+				Hour:   {"%d hours ago", "%d hours ago"},
+				Day:    {"%d days ago", "%d days ago"},
+				Week:   {"%d weeks ago", "%d weeks ago"},
+				Month:  {"%d months ago", "%d months ago"},
+				Year:   {"%d years ago", "%d years ago"},
+			},
+			Future: map[TimeUnit][2]string{
+				Second: {"%d seconds from now", "%d seconds from now"},
+				Minute: {"%d minutes from now", "%d minutes from now"},
+				Hour:   {"%d hours from now", "%d hours from now"},
+				Day:    {"%d days from now", "%d days from now"},
+				Week:   {"%d weeks from now", "%d weeks from now"},
+				Month:  {"%d months from now", "%d months from now"},
+				Year:   {"%d years from now", "%d years from now"},
+			},
+		},
+		"de": {
+			Now: "jetzt",
+			Past: map[TimeUnit][2]string{
+				Second: {"vor %d Sekunde", "vor %d Sekunden"},
+				Minute: {"vor %d Minute", "vor %d Minuten"},
+				Hour:   {"vor %d Stunde", "vor %d Stunden"},
+				Day:    {"vor %d Tag", "vor %d Tagen"},
+				Week:   {"vor %d Woche", "vor %d Wochen"},
+				Month:  {"vor %d Monat", "vor %d Monaten"},
+				Year:   {"vor %d Jahr", "vor %d Jahren"},
+			},
+			Future: map[TimeUnit][2]string{
+				Second: {"in %d Sekunde", "in %d Sekunden"},
+				Minute: {"in %d Minute", "in %d Minuten"},
+				Hour:   {"in %d Stunde", "in %d Stunden"},
+				Day:    {"in %d Tag", "in %d Tagen"},
+				Week:   {"in %d Woche", "in %d Wochen"},
+				Month:  {"in %d Monat", "in %d Monaten"},
+				Year:   {"in %d Jahr", "in %d Jahren"},
+			},
+		},
+	}
+	localesLock sync.RWMutex
+)
+
+// RegisterLocale adds or replaces the phrases of a locale used by TimeAbsoluteFormatterLocale.
+func RegisterLocale(name string, locale Locale) {
+	localesLock.Lock()
+	defer localesLock.Unlock()
+	locales[name] = locale
+}
+
+// TimeAbsoluteFormatterLocale works like TimeAbsoluteFormatter but uses the phrases of the given locale.
+// The built-in locales are "en" and "de", e.g. "vor 3 Tagen" and "in 2 Stunden".
+// Additional locales can be added with RegisterLocale. Unknown locales fall back to "en".
+func TimeAbsoluteFormatterLocale(date time.Time, referenceDate time.Time, locale string) string {
+	return relativeTime(date, referenceDate, locale, Truncate)
+}
+
+// TimeAbsoluteFormatterRounding works like TimeAbsoluteFormatter but rounds the count with the given rounding,
+// e.g. 119 seconds are "1 minutes ago" with Truncate and "2 minutes ago" with Round.
+func TimeAbsoluteFormatterRounding(date time.Time, referenceDate time.Time, rounding Rounding) string {
+	return relativeTime(date, referenceDate, "en", rounding)
+}
+
+func relativeTime(date time.Time, referenceDate time.Time, locale string, rounding Rounding) string {
+	localesLock.RLock()
+	l, ok := locales[locale]
+	if !ok {
+		l = locales["en"]
+	}
+	localesLock.RUnlock()
+
+	duration := referenceDate.Sub(date)
+	if duration == 0 {
+		return l.Now
+	}
+	phrases := l.Past
+	if duration < 0 {
+		duration = -duration
+		phrases = l.Future
+	}
+	unit, n := relativeUnit(duration, rounding)
+	format := phrases[unit][1]
+	if n == 1 {
+		format = phrases[unit][0]
+	}
+	if !strings.Contains(format, "%d") {
+		return format
+	}
+	return fmt.Sprintf(format, n)
+}
+
+// Rounding controls how the relative time formatters round the count of a unit.
+type Rounding int
+
+const (
+	// Truncate rounds down, e.g. 119 seconds are "1 minutes ago".
+	Truncate Rounding = iota
+	// Round rounds to the nearest count, halves up, e.g. 90 seconds are "2 minutes ago".
+	Round
+	// Ceil rounds up, e.g. 61 seconds are "2 minutes ago".
+	Ceil
+)
+
+// unitSizes holds the duration of each unit, a month has 30 days and a year 365 days.
+var unitSizes = map[TimeUnit]time.Duration{
+	Second: time.Second,
+	Minute: time.Minute,
+	Hour:   time.Hour,
+	Day:    24 * time.Hour,
+	Week:   7 * 24 * time.Hour,
+	Month:  30 * 24 * time.Hour,
+	Year:   365 * 24 * time.Hour,
+}
+
+// relativeUnit returns the unit and the rounded count used to describe a positive duration.
+// If the rounded count reaches the threshold of the next unit, e.g. 60 seconds for 59.5 seconds, the count is
+// expressed in the next unit without rounding again, but at least 1, e.g. 12 months are "1 years".
+func relativeUnit(duration time.Duration, rounding Rounding) (TimeUnit, int) {
+	unit := unitOf(duration)
+	size := unitSizes[unit]
+	n := duration / size
+	switch rem := duration % size; {
+	case rounding == Round && rem >= size-rem:
+		n++
+	case rounding == Ceil && rem > 0:
+		n++
+	}
+	if rounded := n * size; rounded > duration {
+		if next := unitOf(rounded); next != unit {
+			return next, max(1, int(rounded/unitSizes[next]))
+		}
+	}
+	return unit, int(n)
+}
+
+// unitOf returns the unit used to describe a positive duration.
+func unitOf(duration time.Duration) TimeUnit {
+	switch {
+	case duration < time.Minute:
+		return Second
+	case duration < time.Hour:
+		return Minute
+	case duration < 24*time.Hour:
+		return Hour
+	case duration < 7*24*time.Hour:
+		return Day
+	case duration < 30*24*time.Hour:
+		return Week
+	case duration < 12*30*24*time.Hour:
+		return Month
+	default:
+		return Year
+	}
+}
@@ -0,0 +1,65 @@
+package formatter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeAbsoluteFormatterLocale(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		date     time.Time
+		locale   string
+		expected string
+	}{
+		{"de now", now, "de", "jetzt"},
+		{"de one second ago", now.Add(-1 * time.Second), "de", "vor 1 Sekunde"},
+		{"de three days ago", now.AddDate(0, 0, -3), "de", "vor 3 Tagen"},
+		{"de one day ago", now.AddDate(0, 0, -1), "de", "vor 1 Tag"},
+		{"de two months ago", now.AddDate(0, 0, -60), "de", "vor 2 Monaten"},
+		{"de in two hours", now.Add(2 * time.Hour), "de", "in 2 Stunden"},
+		{"de in one week", now.AddDate(0, 0, 7), "de", "in 1 Woche"},
+		{"de in two years", now.AddDate(0, 0, 730), "de", "in 2 Jahren"},
+		{"en three days ago", now.AddDate(0, 0, -3), "en", "3 days ago"},
+		{"unknown locale", now.Add(2 * time.Hour), "xx", "2 hours from now"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TimeAbsoluteFormatterLocale(tt.date, now, tt.locale); got != tt.expected {
+				t.Errorf("TimeAbsoluteFormatterLocale() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegisterLocale(t *testing.T) {
+	RegisterLocale("test", Locale{
+		Now:    "right now",
+		Past:   map[TimeUnit][2]string{Hour: {"an hour ago", "%d hours back"}},
+		Future: map[TimeUnit][2]string{Hour: {"in an hour", "in %d hours"}},
+	})
+
+	now := time.Now()
+	if got := TimeAbsoluteFormatterLocale(now.Add(-3*time.Hour), now, "test"); got != "3 hours back" {
+		t.Errorf("TimeAbsoluteFormatterLocale() = %v, want %v", got, "3 hours back")
+	}
+	if got := TimeAbsoluteFormatterLocale(now, now, "test"); got != "right now" {
+		t.Errorf("TimeAbsoluteFormatterLocale() = %v, want %v", got, "right now")
+	}
+	if got := TimeAbsoluteFormatterLocale(now.Add(-1*time.Hour), now, "test"); got != "an hour ago" {
+		t.Errorf("TimeAbsoluteFormatterLocale() = %v, want %v", got, "an hour ago")
+	}
+}
+
+func TestTimeAbsoluteFormatterLocaleMatchesTruncation(t *testing.T) {
+	now := time.Now()
+	for _, days := range []int{29, 359, 360, 362, 365} {
+		date := now.Add(-time.Duration(days) * 24 * time.Hour)
+		if got, want := TimeAbsoluteFormatterLocale(date, now, "en"), TimeAbsoluteFormatter(date, now); got != want {
+			t.Errorf("%d days: TimeAbsoluteFormatterLocale() = %v, want %v", days, got, want)
+		}
+	}
+}
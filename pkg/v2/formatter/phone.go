@@ -0,0 +1,77 @@
+package formatter
+
+import (
+	"strings"
+)
+
+// FormatPhone normalizes a raw phone number and formats it in a readable grouped form.
+// The function takes the raw phone number and a region ("DE" or "US") used to interpret
+// national numbers without a country code, and returns a string.
+// Spaces, dashes, dots, slashes and parentheses are removed and a leading "00" is treated as "+".
+// German numbers are formatted as "+49 151 23456789" for mobile numbers and "+49 30123456" otherwise.
+// US numbers are formatted as "+1 (415) 555-2671".
+// If the number is not recognized, the raw input is returned unchanged.
+func FormatPhone(raw string, region string) string {
+	number := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '/', '(', ')':
+			return -1
+		}
+		return r
+	}, raw)
+
+	if strings.HasPrefix(number, "00") {
+		number = "+" + number[2:]
+	}
+	if number == "" || number == "+" || !isDigits(strings.TrimPrefix(number, "+")) {
+		return raw
+	}
+
+	if !strings.HasPrefix(number, "+") {
+		switch strings.ToUpper(region) {
+		case "DE":
+			if !strings.HasPrefix(number, "0") {
+				return raw
+			}
+			number = "+49" + number[1:]
+		case "US":
+			if len(number) == 11 && strings.HasPrefix(number, "1") {
+				number = number[1:]
+			}
+			number = "+1" + number
+		default:
+			return raw
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(number, "+49"):
+		national := number[3:]
+		if len(national) < 6 || len(national) > 12 || strings.HasPrefix(national, "0") {
+			return raw
+		}
+		if strings.HasPrefix(national, "15") || strings.HasPrefix(national, "16") || strings.HasPrefix(national, "17") {
+			return "+49 " + national[:3] + " " + national[3:]
+		}
+		return "+49 " + national
+	case strings.HasPrefix(number, "+1"):
+		national := number[2:]
+		if len(national) != 10 {
+			return raw
+		}
+		return "+1 (" + national[:3] + ") " + national[3:6] + "-" + national[6:]
+	}
+	return raw
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
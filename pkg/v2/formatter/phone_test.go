@@ -0,0 +1,33 @@
+package formatter
+
+import (
+	"testing"
+)
+
+func TestFormatPhone(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		region   string
+		expected string
+	}{
+		{"german mobile international", "+49 151 2345-6789", "DE", "+49 151 23456789"},
+		{"german mobile with 00", "0049 (151) 23456789", "", "+49 151 23456789"},
+		{"german mobile national", "0151/23456789", "DE", "+49 151 23456789"},
+		{"german landline", "030 123456", "DE", "+49 30123456"},
+		{"us number", "(415) 555-2671", "US", "+1 (415) 555-2671"},
+		{"us number international", "+1 415.555.2671", "", "+1 (415) 555-2671"},
+		{"us number with trunk prefix", "1-415-555-2671", "US", "+1 (415) 555-2671"},
+		{"unparseable", "call me maybe", "DE", "call me maybe"},
+		{"unknown region", "0151 23456789", "FR", "0151 23456789"},
+		{"unknown country", "+33 1 23 45 67 89", "DE", "+33 1 23 45 67 89"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatPhone(tt.raw, tt.region); got != tt.expected {
+				t.Errorf("FormatPhone() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package formatter
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Acronyms holds the words rendered in upper case by TitleCaseFromSnake and TitleCaseFromCamel.
+// Keys must be lower case. Add or remove entries to configure the conversion.
+var Acronyms = map[string]bool{
+	"api":  true,
+	"csv":  true,
+	"html": true,
+	"http": true,
+	"id":   true,
+	"ip":   true,
+	"json": true,
+	"sql":  true,
+	"uri":  true,
+	"url":  true,
+	"uuid": true,
+}
+
+// TitleCaseFromSnake converts a snake_case or kebab-case key to a title cased label.
+// The function takes a string like "first_name" and returns "First Name".
+// Words contained in Acronyms are rendered in upper case, e.g. "user_id" returns "User ID".
+func TitleCaseFromSnake(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || unicode.IsSpace(r)
+	})
+	return titleWords(words)
+}
+
+// TitleCaseFromCamel converts a camelCase or PascalCase key to a title cased label.
+// The function takes a string like "firstName" and returns "First Name".
+// Runs of upper case letters are kept together, so "userID" returns "User ID" and "APIKey" returns "API Key".
+func TitleCaseFromCamel(s string) string {
+	runes := []rune(s)
+	words := []string{}
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		lowerToUpper := !unicode.IsUpper(prev) && unicode.IsUpper(cur)
+		acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || acronymEnd {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return titleWords(words)
+}
+
+func titleWords(words []string) string {
+	for i, word := range words {
+		lower := strings.ToLower(word)
+		if Acronyms[lower] {
+			words[i] = strings.ToUpper(word)
+			continue
+		}
+		runes := []rune(lower)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+// FormatKV renders the map as "key: value" pairs sorted by key and joined by sep.
+// The function takes a map like {"b": "2", "a": "1"} and the separator "\n" and returns "a: 1\nb: 2".
+// An empty map returns an empty string.
+func FormatKV(m map[string]string, sep string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+": "+m[k])
+	}
+	return strings.Join(pairs, sep)
+}
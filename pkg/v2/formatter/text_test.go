@@ -0,0 +1,84 @@
+package formatter
+
+import (
+	"testing"
+)
+
+func TestTitleCaseFromSnake(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"first_name", "First Name"},
+		{"created_at", "Created At"},
+		{"user_id", "User ID"},
+		{"api-key", "API Key"},
+		{"__email__", "Email"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := TitleCaseFromSnake(tt.input); got != tt.expected {
+				t.Errorf("TitleCaseFromSnake() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTitleCaseFromCamel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"firstName", "First Name"},
+		{"FirstName", "First Name"},
+		{"userId", "User ID"},
+		{"userID", "User ID"},
+		{"APIKey", "API Key"},
+		{"email", "Email"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := TitleCaseFromCamel(tt.input); got != tt.expected {
+				t.Errorf("TitleCaseFromCamel() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAcronyms(t *testing.T) {
+	Acronyms["sku"] = true
+	defer delete(Acronyms, "sku")
+
+	if got := TitleCaseFromSnake("product_sku"); got != "Product SKU" {
+		t.Errorf("TitleCaseFromSnake() = %v, want %v", got, "Product SKU")
+	}
+}
+
+func TestFormatKV(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    map[string]string
+		sep      string
+		expected string
+	}{
+		{"sorted", map[string]string{"name": "Max", "email": "max@example.com", "id": "1"}, "\n", "email: max@example.com\nid: 1\nname: Max"},
+		{"separator", map[string]string{"b": "2", "a": "1"}, ", ", "a: 1, b: 2"},
+		{"single", map[string]string{"a": "1"}, ", ", "a: 1"},
+		{"empty", map[string]string{}, "\n", ""},
+		{"nil", nil, "\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 5; i++ {
+				if got := FormatKV(tt.input, tt.sep); got != tt.expected {
+					t.Errorf("FormatKV() = %q, want %q", got, tt.expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package signal
+
+import (
+	"fmt"
+)
+
+// CallbackResult is a callback contributing a value to EmitCollectMap.
+type CallbackResult func(signal Signal, data interface{}) interface{}
+
+type responder struct {
+	name     string
+	callback CallbackResult
+}
+
+// ConnectNamed registers a callback for a signal emitted with EmitCollectMap.
+// Its result is collected under the name, which must be unique for the
+// signal.
+func (d *SignalDispatcher) ConnectNamed(signal Signal, name string, callback CallbackResult) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, r := range d.responders[signal] {
+		if r.name == name {
+			return fmt.Errorf("responder %s already connected to %s", name, signal)
+		}
+	}
+	if d.responders == nil {
+		d.responders = make(map[Signal][]responder)
+	}
+	d.responders[signal] = append(d.responders[signal], responder{name: name, callback: callback})
+	return nil
+}
+
+// EmitCollectMap calls the callbacks connected with ConnectNamed one after
+// another and returns their results keyed by the names of the callbacks, e.g.
+// to collect the capabilities of plugins.
+func (d *SignalDispatcher) EmitCollectMap(signal Signal, data interface{}) map[string]interface{} {
+	d.lock.Lock()
+	responders := append([]responder{}, d.responders[signal]...)
+	d.lock.Unlock()
+
+	results := make(map[string]interface{}, len(responders))
+	for _, r := range responders {
+		results[r.name] = r.callback(signal, data)
+	}
+	return results
+}
@@ -0,0 +1,20 @@
+package signal
+
+// ErrorSignal is the signal emitted by EmitError.
+const ErrorSignal Signal = "error"
+
+// EmitError emits the error as ErrorSignal, so a central handler connected
+// with OnError can log or report errors of all components.
+func (d *SignalDispatcher) EmitError(err error, opts ...EmitOpt) {
+	d.Emit(ErrorSignal, err, opts...)
+}
+
+// OnError registers a handler for errors emitted with EmitError. Data of the
+// ErrorSignal that is not an error is ignored.
+func (d *SignalDispatcher) OnError(handler func(err error)) {
+	d.Connect(ErrorSignal, func(signal Signal, data interface{}) {
+		if err, ok := data.(error); ok {
+			handler(err)
+		}
+	})
+}
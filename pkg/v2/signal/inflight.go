@@ -0,0 +1,48 @@
+package signal
+
+import (
+	"sync"
+)
+
+// inFlight counts the running callbacks of a dispatcher. The zero value is
+// ready to use and a nil *inFlight ignores all calls.
+type inFlight struct {
+	lock sync.Mutex
+	cond *sync.Cond
+	n    int
+}
+
+func (f *inFlight) add(delta int) {
+	if f == nil {
+		return
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.n += delta
+	if f.n == 0 && f.cond != nil {
+		f.cond.Broadcast()
+	}
+}
+
+// InFlight returns the number of callbacks that are currently running,
+// including the attempts of callbacks connected with ConnectRetrying.
+func (d *SignalDispatcher) InFlight() int {
+	d.inFlight.lock.Lock()
+	defer d.inFlight.lock.Unlock()
+	return d.inFlight.n
+}
+
+// Wait blocks until no callbacks are running, e.g. to drain non-blocking
+// emits before shutting down. Signals queued by a dispatcher created with
+// NewSerialDispatcher are not waited for, use Close instead.
+func (d *SignalDispatcher) Wait() {
+	f := &d.inFlight
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.cond == nil {
+		f.cond = sync.NewCond(&f.lock)
+	}
+	for f.n > 0 {
+		f.cond.Wait()
+	}
+}
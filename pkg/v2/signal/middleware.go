@@ -0,0 +1,36 @@
+package signal
+
+import (
+	"time"
+)
+
+// Middleware wraps the callbacks of a dispatcher, e.g. to log or measure them.
+type Middleware func(next Callback) Callback
+
+// Use adds middleware that wraps every callback called by the dispatcher. The
+// middleware added first is the outermost.
+func (d *SignalDispatcher) Use(middleware ...Middleware) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.middleware = append(d.middleware, middleware...)
+}
+
+// chain wraps the callback with the middleware.
+func chain(middleware []Middleware, callback Callback) Callback {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		callback = middleware[i](callback)
+	}
+	return callback
+}
+
+// TimingMiddleware returns a middleware that calls record with the signal and
+// the duration of each callback.
+func TimingMiddleware(record func(signal Signal, d time.Duration)) Middleware {
+	return func(next Callback) Callback {
+		return func(signal Signal, data interface{}) {
+			start := time.Now()
+			next(signal, data)
+			record(signal, time.Since(start))
+		}
+	}
+}
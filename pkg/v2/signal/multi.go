@@ -0,0 +1,59 @@
+package signal
+
+import (
+	"sync"
+)
+
+// MultiDispatcher broadcasts signals to a set of dispatchers, e.g. one per
+// plugin.
+type MultiDispatcher struct {
+	members []*SignalDispatcher
+}
+
+// NewMultiDispatcher creates a MultiDispatcher for the given dispatchers.
+func NewMultiDispatcher(members ...*SignalDispatcher) *MultiDispatcher {
+	return &MultiDispatcher{members: members}
+}
+
+// Connect registers the callback on every member, so it also receives signals
+// emitted directly on a single member.
+func (m *MultiDispatcher) Connect(signal Signal, callback Callback) {
+	l := newListener(callback)
+	for _, d := range m.members {
+		d.connect(signal, l)
+	}
+}
+
+// Emit emits the signal to the callbacks of all members, executing them in
+// parallel and waiting for them to finish. Each member runs its callbacks with
+// its own settings: MaxConcurrency applies per member, the callbacks are
+// counted in its InFlight, and a member created with NewSerialDispatcher
+// queues them instead of running them right away. A callback connected
+// through the MultiDispatcher is called only once, even though it is
+// registered on every member.
+func (m *MultiDispatcher) Emit(signal Signal, data interface{}) {
+	seen := map[uint64]bool{}
+	var wg sync.WaitGroup
+	for _, d := range m.members {
+		listeners := []listener{}
+		for _, l := range d.listenersOf(signal) {
+			if !seen[l.id] {
+				seen[l.id] = true
+				listeners = append(listeners, l)
+			}
+		}
+		if len(listeners) == 0 {
+			continue
+		}
+		if d.serial != nil {
+			d.serial.push(event{signal: signal, data: data, listeners: listeners})
+			continue
+		}
+		wg.Add(1)
+		go func(d *SignalDispatcher) {
+			defer wg.Done()
+			run(signal, data, listeners, true, d.MaxConcurrency, &d.inFlight)
+		}(d)
+	}
+	wg.Wait()
+}
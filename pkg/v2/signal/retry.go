@@ -0,0 +1,36 @@
+package signal
+
+import (
+	"time"
+)
+
+// CallbackErr is a callback that reports failure by returning an error.
+type CallbackErr func(signal Signal, data interface{}) error
+
+// ConnectRetrying registers a callback that is called again if it returns an
+// error, up to attempts times in total with the backoff between the attempts.
+// The attempts run in their own goroutine, so Emit does not wait for them. If
+// the last attempt fails, the error is passed to RetryFailed.
+func (d *SignalDispatcher) ConnectRetrying(signal Signal, callback CallbackErr, attempts int, backoff time.Duration) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	d.Connect(signal, func(signal Signal, data interface{}) {
+		d.inFlight.add(1)
+		go func() {
+			defer d.inFlight.add(-1)
+			var err error
+			for i := 0; i < attempts; i++ {
+				if i > 0 {
+					time.Sleep(backoff)
+				}
+				if err = callback(signal, data); err == nil {
+					return
+				}
+			}
+			if d.RetryFailed != nil {
+				d.RetryFailed(signal, data, err)
+			}
+		}()
+	})
+}
@@ -0,0 +1,93 @@
+package signal
+
+import (
+	"sync"
+)
+
+type event struct {
+	signal Signal
+	tag    string
+	data   interface{}
+	// listeners, if set, are called instead of the listeners of the signal,
+	// e.g. by MultiDispatcher.Emit.
+	listeners []listener
+}
+
+// serialQueue is an unbounded FIFO queue of emitted events drained by a
+// single goroutine.
+type serialQueue struct {
+	events []event
+	closed bool
+	cond   *sync.Cond
+	done   chan struct{}
+}
+
+// NewSerialDispatcher creates a SignalDispatcher that processes signals strictly
+// in emission order. Emit appends the signal to an internal queue and returns
+// immediately. A single goroutine drains the queue and calls the callbacks of
+// each signal sequentially, so the global emission order is preserved across
+// different signals.
+//
+// Call Close to process the remaining signals and stop the dispatcher.
+func NewSerialDispatcher() *SignalDispatcher {
+	d := NewSignalDispatcher()
+	d.serial = &serialQueue{
+		cond: sync.NewCond(&sync.Mutex{}),
+		done: make(chan struct{}),
+	}
+	go d.drain()
+	return d
+}
+
+// Close processes all queued signals and stops a dispatcher created with
+// NewSerialDispatcher. Signals emitted after Close are dropped. Close has no
+// effect on other dispatchers.
+func (d *SignalDispatcher) Close() {
+	if d.serial == nil {
+		return
+	}
+	q := d.serial
+	q.cond.L.Lock()
+	q.closed = true
+	q.cond.L.Unlock()
+	q.cond.Signal()
+	<-q.done
+}
+
+func (q *serialQueue) push(e event) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.closed {
+		return
+	}
+	q.events = append(q.events, e)
+	q.cond.Signal()
+}
+
+func (d *SignalDispatcher) drain() {
+	q := d.serial
+	defer close(q.done)
+	for {
+		q.cond.L.Lock()
+		for len(q.events) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if len(q.events) == 0 {
+			q.cond.L.Unlock()
+			return
+		}
+		e := q.events[0]
+		q.events = q.events[1:]
+		q.cond.L.Unlock()
+
+		listeners := e.listeners
+		if listeners == nil {
+			listeners = withTag(d.listenersOf(e.signal), e.tag)
+		}
+		for _, l := range listeners {
+			d.inFlight.add(1)
+			l.callback(e.signal, e.data)
+			d.inFlight.add(-1)
+		}
+	}
+}
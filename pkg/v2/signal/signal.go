@@ -41,7 +41,10 @@
 package signal
 
 import (
+	"errors"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Signal type for demonstration
@@ -50,45 +53,252 @@ type Signal string
 // Callback function type
 type Callback func(signal Signal, data interface{})
 
+// listener is a registered callback. The id identifies the registration, so
+// the same registration connected to several dispatchers can be recognized.
+// The tag is set for callbacks connected with ConnectTagged.
+type listener struct {
+	id       uint64
+	tag      string
+	callback Callback
+}
+
+var lastListenerID atomic.Uint64
+
+func newListener(callback Callback) listener {
+	return listener{id: lastListenerID.Add(1), callback: callback}
+}
+
 // SignalDispatcher to hold registered callbacks
 type SignalDispatcher struct {
-	listeners map[Signal][]Callback
-	lock      sync.Mutex
+	// DefaultBlocking controls whether Emit waits for the callbacks to finish.
+	// It is true for dispatchers created with NewSignalDispatcher and can be
+	// overridden per call with WithBlocking.
+	DefaultBlocking bool
+	// MaxConcurrency limits how many callbacks of a single Emit run at the
+	// same time. Zero means unlimited.
+	MaxConcurrency int
+	// RetryFailed, if set, is called when a callback connected with
+	// ConnectRetrying still fails after all attempts.
+	RetryFailed func(signal Signal, data interface{}, err error)
+	// TraceHook, if set, is called for each signal emitted with EmitTraced or
+	// TraceEnvelope.Emit. The parent is empty for the first signal of a trace.
+	TraceHook func(traceID string, parent Signal, child Signal)
+	// SubscriptionBuffer is the buffer size of the channels returned by
+	// Subscribe. Zero means DefaultSubscriptionBuffer.
+	SubscriptionBuffer int
+
+	listeners  map[Signal][]listener
+	sticky     map[Signal]interface{}
+	vetoers    map[Signal][]vetoer
+	responders map[Signal][]responder
+	middleware []Middleware
+	lock       sync.Mutex
+	inFlight   inFlight
+
+	// serial is set for dispatchers created with NewSerialDispatcher.
+	serial *serialQueue
 }
 
 // NewSignalDispatcher creates a new instance of SignalDispatcher
 func NewSignalDispatcher() *SignalDispatcher {
 	return &SignalDispatcher{
-		listeners: make(map[Signal][]Callback),
+		DefaultBlocking: true,
+		listeners:       make(map[Signal][]listener),
+	}
+}
+
+type emitOptions struct {
+	blocking bool
+}
+
+// EmitOpt configures a single call to Emit.
+type EmitOpt func(*emitOptions)
+
+// WithBlocking overrides DefaultBlocking for a single call to Emit.
+func WithBlocking(blocking bool) EmitOpt {
+	return func(o *emitOptions) {
+		o.blocking = blocking
 	}
 }
 
 // Connect registers a callback for a given signal
 func (d *SignalDispatcher) Connect(signal Signal, callback Callback) {
+	d.connect(signal, newListener(callback))
+}
+
+func (d *SignalDispatcher) connect(signal Signal, l listener) {
 	d.lock.Lock()
 	defer d.lock.Unlock()
 
 	if _, exists := d.listeners[signal]; !exists {
-		d.listeners[signal] = []Callback{}
+		d.listeners[signal] = []listener{}
 	}
-	d.listeners[signal] = append(d.listeners[signal], callback)
+	d.listeners[signal] = append(d.listeners[signal], l)
 }
 
-// Send emits a signal to all registered callbacks, executing them in parallel
-func (d *SignalDispatcher) Emit(signal Signal, data interface{}) {
+// disconnect removes the listener with the given id.
+func (d *SignalDispatcher) disconnect(signal Signal, id uint64) {
 	d.lock.Lock()
-	callbacks, exists := d.listeners[signal]
-	d.lock.Unlock() // Unlock as soon as possible, before invoking callbacks
-
-	if exists {
-		var wg sync.WaitGroup
-		for _, callback := range callbacks {
-			wg.Add(1)
-			go func(cb Callback) {
-				defer wg.Done()
-				cb(signal, data)
-			}(callback)
+	defer d.lock.Unlock()
+
+	listeners := []listener{}
+	for _, l := range d.listeners[signal] {
+		if l.id != id {
+			listeners = append(listeners, l)
 		}
+	}
+	d.listeners[signal] = listeners
+}
+
+// listenersOf returns a copy of the listeners registered for the signal with
+// the middleware applied to their callbacks.
+func (d *SignalDispatcher) listenersOf(signal Signal) []listener {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.listenersLocked(signal)
+}
+
+// listenersLocked works like listenersOf, the caller holds the lock.
+func (d *SignalDispatcher) listenersLocked(signal Signal) []listener {
+	listeners := append([]listener{}, d.listeners[signal]...)
+	for i := range listeners {
+		listeners[i].callback = chain(d.middleware, listeners[i].callback)
+	}
+	return listeners
+}
+
+// Send emits a signal to all registered callbacks, executing them in parallel.
+// By default Emit waits for all callbacks to finish, see DefaultBlocking and
+// WithBlocking. Options are ignored by dispatchers created with
+// NewSerialDispatcher.
+func (d *SignalDispatcher) Emit(signal Signal, data interface{}, opts ...EmitOpt) {
+	d.emit(signal, "", data, opts)
+}
+
+// emit emits the signal to the listeners with the given tag, or to all
+// listeners if the tag is empty.
+func (d *SignalDispatcher) emit(signal Signal, tag string, data interface{}, opts []EmitOpt) {
+	if d.serial != nil {
+		d.serial.push(event{signal: signal, tag: tag, data: data})
+		return
+	}
+	d.emitTo(signal, data, withTag(d.listenersOf(signal), tag), opts)
+}
+
+// emitTo emits the signal to the given listeners instead of the listeners
+// registered at the time of the emit.
+func (d *SignalDispatcher) emitTo(signal Signal, data interface{}, listeners []listener, opts []EmitOpt) {
+	options := emitOptions{blocking: d.DefaultBlocking}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if d.serial != nil {
+		d.serial.push(event{signal: signal, data: data, listeners: listeners})
+		return
+	}
+
+	run(signal, data, listeners, options.blocking, d.MaxConcurrency, &d.inFlight)
+}
+
+// run executes the listeners in parallel and waits for them if blocking is set.
+// If maxConcurrency is greater than zero, a pool of at most that many
+// goroutines runs the listeners, so emitting to thousands of listeners does
+// not start thousands of goroutines. The listeners are counted in the
+// tracker, if set.
+func run(signal Signal, data interface{}, listeners []listener, blocking bool, maxConcurrency int, tracker *inFlight) {
+	workers := len(listeners)
+	if maxConcurrency > 0 && maxConcurrency < workers {
+		workers = maxConcurrency
+	}
+
+	callbacks := make(chan Callback, len(listeners))
+	for _, l := range listeners {
+		tracker.add(1)
+		callbacks <- l.callback
+	}
+	close(callbacks)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for cb := range callbacks {
+				cb(signal, data)
+				tracker.add(-1)
+			}
+		}()
+	}
+	if blocking {
 		wg.Wait()
 	}
 }
+
+// DispatcherState holds a copy of the registered callbacks of a dispatcher.
+type DispatcherState struct {
+	listeners map[Signal][]listener
+}
+
+// Snapshot returns a copy of the registered callbacks. Callbacks connected
+// after the snapshot are not part of it.
+func (d *SignalDispatcher) Snapshot() DispatcherState {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return DispatcherState{listeners: copyListeners(d.listeners)}
+}
+
+// Restore replaces the registered callbacks with the ones of the snapshot.
+// This is useful to isolate registrations made by tests.
+func (d *SignalDispatcher) Restore(state DispatcherState) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.listeners = copyListeners(state.listeners)
+}
+
+func copyListeners(listeners map[Signal][]listener) map[Signal][]listener {
+	c := make(map[Signal][]listener, len(listeners))
+	for signal, l := range listeners {
+		c[signal] = append([]listener{}, l...)
+	}
+	return c
+}
+
+// ErrRequestTimeout is returned by Request if no reply is received in time.
+var ErrRequestTimeout = errors.New("signal request timed out")
+
+// RequestEnvelope wraps the data passed to callbacks by Request. A responder
+// answers the request by calling Reply.
+type RequestEnvelope struct {
+	Data  interface{}
+	reply chan interface{}
+	once  sync.Once
+}
+
+// Reply sends the reply for the request. Only the first reply is delivered,
+// later calls are ignored.
+func (e *RequestEnvelope) Reply(v interface{}) {
+	e.once.Do(func() {
+		e.reply <- v
+	})
+}
+
+// Request emits a signal with the data wrapped in a *RequestEnvelope and waits
+// for a reply. It returns the first reply or ErrRequestTimeout if no reply is
+// received within the timeout.
+//
+// Request is intended for signals with a single responder.
+func (d *SignalDispatcher) Request(signal Signal, data interface{}, timeout time.Duration) (interface{}, error) {
+	envelope := &RequestEnvelope{
+		Data:  data,
+		reply: make(chan interface{}, 1),
+	}
+	go d.Emit(signal, envelope)
+
+	select {
+	case v := <-envelope.reply:
+		return v, nil
+	case <-time.After(timeout):
+		return nil, ErrRequestTimeout
+	}
+}
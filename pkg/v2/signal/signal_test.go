@@ -0,0 +1,835 @@
+package signal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequest(t *testing.T) {
+	t.Run("Reply", func(t *testing.T) {
+		d := NewSignalDispatcher()
+		d.Connect("ping", func(signal Signal, data interface{}) {
+			envelope := data.(*RequestEnvelope)
+			envelope.Reply(envelope.Data.(string) + " pong")
+		})
+
+		v, err := d.Request("ping", "ping", time.Second)
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err)
+		}
+		if v != "ping pong" {
+			t.Errorf("Expected ping pong, got %v", v)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		d := NewSignalDispatcher()
+
+		_, err := d.Request("ping", "ping", 10*time.Millisecond)
+		if !errors.Is(err, ErrRequestTimeout) {
+			t.Errorf("Expected ErrRequestTimeout, got %v", err)
+		}
+	})
+}
+
+func TestSerialDispatcher(t *testing.T) {
+	d := NewSerialDispatcher()
+
+	var received []interface{}
+	record := func(signal Signal, data interface{}) {
+		received = append(received, data)
+	}
+	d.Connect("a", record)
+	d.Connect("b", record)
+
+	for i := 0; i < 100; i++ {
+		if i%3 == 0 {
+			d.Emit("a", i)
+		} else {
+			d.Emit("b", i)
+		}
+	}
+	d.Close()
+
+	if len(received) != 100 {
+		t.Fatalf("Expected 100 callbacks, got %d", len(received))
+	}
+	for i, v := range received {
+		if v != i {
+			t.Fatalf("Expected %d at position %d, got %v", i, i, v)
+		}
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	d := NewSignalDispatcher()
+	var before, after int
+	d.Connect("a", func(signal Signal, data interface{}) { before++ })
+
+	state := d.Snapshot()
+	d.Connect("a", func(signal Signal, data interface{}) { after++ })
+	d.Connect("b", func(signal Signal, data interface{}) { after++ })
+	d.Restore(state)
+
+	d.Emit("a", nil)
+	d.Emit("b", nil)
+	if before != 1 {
+		t.Errorf("Expected original listener to be called once, got %d", before)
+	}
+	if after != 0 {
+		t.Errorf("Expected new listeners to be removed, got %d calls", after)
+	}
+
+	// later registrations must not change the snapshot
+	d.Connect("a", func(signal Signal, data interface{}) { after++ })
+	d.Restore(state)
+	d.Emit("a", nil)
+	if after != 0 {
+		t.Errorf("Expected snapshot to be unaffected by later registrations, got %d calls", after)
+	}
+}
+
+func TestEmitBlocking(t *testing.T) {
+	slow := func(done chan struct{}) Callback {
+		return func(signal Signal, data interface{}) {
+			time.Sleep(50 * time.Millisecond)
+			close(done)
+		}
+	}
+
+	t.Run("Blocking", func(t *testing.T) {
+		d := NewSignalDispatcher()
+		done := make(chan struct{})
+		d.Connect("a", slow(done))
+
+		d.Emit("a", nil)
+		select {
+		case <-done:
+		default:
+			t.Errorf("Expected Emit to wait for the callback")
+		}
+	})
+
+	t.Run("Non-blocking", func(t *testing.T) {
+		d := NewSignalDispatcher()
+		done := make(chan struct{})
+		d.Connect("a", slow(done))
+
+		d.Emit("a", nil, WithBlocking(false))
+		select {
+		case <-done:
+			t.Errorf("Expected Emit to return before the callback finished")
+		default:
+		}
+		<-done
+	})
+
+	t.Run("Default non-blocking", func(t *testing.T) {
+		d := NewSignalDispatcher()
+		d.DefaultBlocking = false
+		done := make(chan struct{})
+		d.Connect("a", slow(done))
+
+		d.Emit("a", nil, WithBlocking(true))
+		select {
+		case <-done:
+		default:
+			t.Errorf("Expected WithBlocking(true) to override the default")
+		}
+	})
+}
+
+func TestMultiDispatcher(t *testing.T) {
+	a := NewSignalDispatcher()
+	b := NewSignalDispatcher()
+	m := NewMultiDispatcher(a, b)
+
+	var lock sync.Mutex
+	calls := map[string]int{}
+	record := func(name string) Callback {
+		return func(signal Signal, data interface{}) {
+			lock.Lock()
+			defer lock.Unlock()
+			calls[name]++
+		}
+	}
+	a.Connect("started", record("plugin-a"))
+	b.Connect("started", record("plugin-b"))
+	m.Connect("started", record("shared"))
+
+	m.Emit("started", nil)
+	if calls["plugin-a"] != 1 || calls["plugin-b"] != 1 {
+		t.Errorf("Expected both plugins to receive the signal, got %v", calls)
+	}
+	if calls["shared"] != 1 {
+		t.Errorf("Expected shared callback to be called once, got %d", calls["shared"])
+	}
+
+	a.Emit("started", nil)
+	if calls["shared"] != 2 || calls["plugin-b"] != 1 {
+		t.Errorf("Expected member emit to reach only its callbacks, got %v", calls)
+	}
+}
+
+func TestMultiDispatcherMemberSettings(t *testing.T) {
+	limited := NewSignalDispatcher()
+	limited.MaxConcurrency = 1
+	serial := NewSerialDispatcher()
+	m := NewMultiDispatcher(limited, serial)
+
+	var inFlight, maxInFlight atomic.Int32
+	var observed atomic.Int32
+	for i := 0; i < 5; i++ {
+		limited.Connect("work", func(signal Signal, data interface{}) {
+			n := inFlight.Add(1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			observed.Store(int32(limited.InFlight()))
+			time.Sleep(2 * time.Millisecond)
+			inFlight.Add(-1)
+		})
+	}
+	var order []interface{}
+	serial.Connect("work", func(signal Signal, data interface{}) {
+		order = append(order, data)
+	})
+
+	m.Emit("work", 1)
+	serial.Emit("work", 2)
+	m.Emit("work", 3)
+	serial.Close()
+
+	if maxInFlight.Load() != 1 {
+		t.Errorf("Expected MaxConcurrency of the member to apply, got %d in flight", maxInFlight.Load())
+	}
+	if observed.Load() < 1 {
+		t.Errorf("Expected the callbacks to be counted in InFlight of the member")
+	}
+	if len(order) != 3 || order[0] != 1 || order[1] != 2 || order[2] != 3 {
+		t.Errorf("Expected the serial member to queue the signals in order, got %v", order)
+	}
+}
+
+func TestSticky(t *testing.T) {
+	d := NewSignalDispatcher()
+
+	d.EmitSticky("started", "v1")
+	d.EmitSticky("started", "v2")
+
+	var received []interface{}
+	d.ConnectSticky("started", func(signal Signal, data interface{}) {
+		received = append(received, data)
+	})
+	if len(received) != 1 || received[0] != "v2" {
+		t.Fatalf("Expected immediate delivery of v2, got %v", received)
+	}
+
+	d.Emit("started", "v3")
+	if len(received) != 2 || received[1] != "v3" {
+		t.Errorf("Expected later emits to be delivered, got %v", received)
+	}
+
+	var plain []interface{}
+	d.Connect("started", func(signal Signal, data interface{}) {
+		plain = append(plain, data)
+	})
+	if len(plain) != 0 {
+		t.Errorf("Expected Connect not to replay sticky values, got %v", plain)
+	}
+
+	var none []interface{}
+	d.ConnectSticky("other", func(signal Signal, data interface{}) {
+		none = append(none, data)
+	})
+	if len(none) != 0 {
+		t.Errorf("Expected no delivery without sticky value, got %v", none)
+	}
+}
+
+func TestStickyConcurrentConnect(t *testing.T) {
+	for i := 0; i < 2000; i++ {
+		d := NewSignalDispatcher()
+		d.EmitSticky("config", "v1")
+
+		var lock sync.Mutex
+		received := map[interface{}]int{}
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			d.EmitSticky("config", "v2")
+		}()
+		go func() {
+			defer wg.Done()
+			d.ConnectSticky("config", func(signal Signal, data interface{}) {
+				lock.Lock()
+				defer lock.Unlock()
+				received[data]++
+			})
+		}()
+		wg.Wait()
+
+		if received["v2"] != 1 || received["v1"] > 1 {
+			t.Fatalf("Expected v2 exactly once, got %v", received)
+		}
+	}
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	d := NewSignalDispatcher()
+	d.MaxConcurrency = 3
+
+	var inFlight, maxInFlight, calls atomic.Int32
+	for i := 0; i < 20; i++ {
+		d.Connect("work", func(signal Signal, data interface{}) {
+			n := inFlight.Add(1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+			calls.Add(1)
+		})
+	}
+
+	d.Emit("work", nil)
+	if calls.Load() != 20 {
+		t.Errorf("Expected 20 calls, got %d", calls.Load())
+	}
+	if maxInFlight.Load() > 3 {
+		t.Errorf("Expected at most 3 callbacks in flight, got %d", maxInFlight.Load())
+	}
+	if maxInFlight.Load() < 2 {
+		t.Errorf("Expected callbacks to run concurrently, got %d in flight", maxInFlight.Load())
+	}
+}
+
+func TestMaxConcurrencyNonBlocking(t *testing.T) {
+	d := NewSignalDispatcher()
+	d.MaxConcurrency = 4
+	d.DefaultBlocking = false
+
+	started := make(chan struct{}, 1000)
+	release := make(chan struct{})
+	var inFlight, maxInFlight, calls atomic.Int32
+	for i := 0; i < 1000; i++ {
+		d.Connect("work", func(signal Signal, data interface{}) {
+			n := inFlight.Add(1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			started <- struct{}{}
+			<-release
+			inFlight.Add(-1)
+			calls.Add(1)
+		})
+	}
+
+	d.Emit("work", nil)
+	for i := 0; i < 4; i++ {
+		<-started
+	}
+	close(release)
+	d.Wait()
+	if calls.Load() != 1000 {
+		t.Errorf("Expected 1000 calls, got %d", calls.Load())
+	}
+	if maxInFlight.Load() != 4 {
+		t.Errorf("Expected 4 callbacks in flight, got %d", maxInFlight.Load())
+	}
+}
+
+func TestTagged(t *testing.T) {
+	d := NewSignalDispatcher()
+
+	var lock sync.Mutex
+	received := map[string]int{}
+	record := func(name string) Callback {
+		return func(signal Signal, data interface{}) {
+			lock.Lock()
+			defer lock.Unlock()
+			received[name]++
+		}
+	}
+	d.ConnectTagged("reload", "tenant-a", record("a1"))
+	d.ConnectTagged("reload", "tenant-a", record("a2"))
+	d.ConnectTagged("reload", "tenant-b", record("b"))
+	d.Connect("reload", record("all"))
+
+	d.EmitTagged("reload", "tenant-a", nil)
+	if received["a1"] != 1 || received["a2"] != 1 || received["b"] != 0 || received["all"] != 0 {
+		t.Errorf("Expected only tenant-a callbacks, got %v", received)
+	}
+
+	d.Emit("reload", nil)
+	if received["a1"] != 2 || received["b"] != 1 || received["all"] != 1 {
+		t.Errorf("Expected Emit to reach all callbacks, got %v", received)
+	}
+
+	t.Run("Serial", func(t *testing.T) {
+		d := NewSerialDispatcher()
+		var calls []string
+		d.ConnectTagged("reload", "tenant-a", func(signal Signal, data interface{}) {
+			calls = append(calls, "a")
+		})
+		d.ConnectTagged("reload", "tenant-b", func(signal Signal, data interface{}) {
+			calls = append(calls, "b")
+		})
+		d.EmitTagged("reload", "tenant-b", nil)
+		d.Close()
+		if len(calls) != 1 || calls[0] != "b" {
+			t.Errorf("Expected only tenant-b callback, got %v", calls)
+		}
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("Order", func(t *testing.T) {
+		d := NewSignalDispatcher()
+		var calls []string
+		trace := func(name string) Middleware {
+			return func(next Callback) Callback {
+				return func(signal Signal, data interface{}) {
+					calls = append(calls, name)
+					next(signal, data)
+				}
+			}
+		}
+		d.Use(trace("outer"), trace("inner"))
+		d.Connect("saved", func(signal Signal, data interface{}) {
+			calls = append(calls, "callback")
+		})
+		d.Emit("saved", nil)
+		if len(calls) != 3 || calls[0] != "outer" || calls[1] != "inner" || calls[2] != "callback" {
+			t.Errorf("Unexpected call order: %v", calls)
+		}
+	})
+
+	t.Run("Timing", func(t *testing.T) {
+		for name, d := range map[string]*SignalDispatcher{"Parallel": NewSignalDispatcher(), "Serial": NewSerialDispatcher()} {
+			t.Run(name, func(t *testing.T) {
+				var lock sync.Mutex
+				durations := map[Signal][]time.Duration{}
+				d.Use(TimingMiddleware(func(signal Signal, d time.Duration) {
+					lock.Lock()
+					defer lock.Unlock()
+					durations[signal] = append(durations[signal], d)
+				}))
+
+				sleep := func(signal Signal, data interface{}) {
+					time.Sleep(time.Millisecond)
+				}
+				d.Connect("a", sleep)
+				d.Connect("a", sleep)
+				d.Connect("b", sleep)
+
+				d.Emit("a", nil)
+				d.Emit("b", nil)
+				d.Close()
+
+				lock.Lock()
+				defer lock.Unlock()
+				if len(durations["a"]) != 2 || len(durations["b"]) != 1 {
+					t.Fatalf("Expected one record per callback, got %v", durations)
+				}
+				for signal, ds := range durations {
+					for _, d := range ds {
+						if d < time.Millisecond {
+							t.Errorf("Expected duration of at least 1ms for %s, got %s", signal, d)
+						}
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestEmitError(t *testing.T) {
+	d := NewSignalDispatcher()
+
+	var received []error
+	d.OnError(func(err error) {
+		received = append(received, err)
+	})
+
+	errFailed := errors.New("sync failed")
+	d.EmitError(errFailed)
+	d.Emit(ErrorSignal, "not an error")
+
+	if len(received) != 1 || !errors.Is(received[0], errFailed) {
+		t.Errorf("Expected handler to receive the error, got %v", received)
+	}
+}
+
+func TestConnectRetrying(t *testing.T) {
+	t.Run("Succeeds", func(t *testing.T) {
+		d := NewSignalDispatcher()
+		var calls atomic.Int32
+		done := make(chan struct{})
+		d.ConnectRetrying("webhook", func(signal Signal, data interface{}) error {
+			if calls.Add(1) < 3 {
+				return errors.New("unavailable")
+			}
+			close(done)
+			return nil
+		}, 5, time.Millisecond)
+		d.RetryFailed = func(signal Signal, data interface{}, err error) {
+			t.Errorf("Expected no failure, got %v", err)
+		}
+
+		d.Emit("webhook", nil)
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Expected callback to succeed")
+		}
+		if calls.Load() != 3 {
+			t.Errorf("Expected 3 calls, got %d", calls.Load())
+		}
+	})
+
+	t.Run("Exhausted", func(t *testing.T) {
+		d := NewSignalDispatcher()
+		var calls atomic.Int32
+		failed := make(chan error, 1)
+		d.RetryFailed = func(signal Signal, data interface{}, err error) {
+			failed <- err
+		}
+		d.ConnectRetrying("webhook", func(signal Signal, data interface{}) error {
+			calls.Add(1)
+			return errors.New("unavailable")
+		}, 2, time.Millisecond)
+
+		d.Emit("webhook", nil)
+		select {
+		case err := <-failed:
+			if err == nil || err.Error() != "unavailable" {
+				t.Errorf("Expected last error, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected RetryFailed to be called")
+		}
+		if calls.Load() != 2 {
+			t.Errorf("Expected 2 calls, got %d", calls.Load())
+		}
+	})
+}
+
+func TestWait(t *testing.T) {
+	d := NewSignalDispatcher()
+
+	var done atomic.Int32
+	release := make(chan struct{})
+	d.Connect("job", func(signal Signal, data interface{}) {
+		<-release
+		time.Sleep(5 * time.Millisecond)
+		done.Add(1)
+	})
+
+	d.Emit("job", nil, WithBlocking(false))
+	d.Emit("job", nil, WithBlocking(false))
+	if n := d.InFlight(); n != 2 {
+		t.Errorf("Expected 2 callbacks in flight, got %d", n)
+	}
+
+	waited := make(chan struct{})
+	go func() {
+		d.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+		t.Fatal("Expected Wait to block while callbacks are running")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Wait to return")
+	}
+	if done.Load() != 2 || d.InFlight() != 0 {
+		t.Errorf("Expected all callbacks to finish, got %d done and %d in flight", done.Load(), d.InFlight())
+	}
+
+	d.Wait()
+}
+
+func TestEmitVetoable(t *testing.T) {
+	d := NewSignalDispatcher()
+
+	var calls []int
+	errProtected := errors.New("user is protected")
+	d.ConnectVetoable("before-delete", func(signal Signal, data interface{}) error {
+		calls = append(calls, 1)
+		return nil
+	})
+	d.ConnectVetoable("before-delete", func(signal Signal, data interface{}) error {
+		calls = append(calls, 2)
+		if data == "admin" {
+			return errProtected
+		}
+		return nil
+	})
+	d.ConnectVetoable("before-delete", func(signal Signal, data interface{}) error {
+		calls = append(calls, 3)
+		return nil
+	})
+
+	if err := d.EmitVetoable("before-delete", "admin"); !errors.Is(err, errProtected) {
+		t.Errorf("Expected veto, got %v", err)
+	}
+	if len(calls) != 2 || calls[0] != 1 || calls[1] != 2 {
+		t.Errorf("Expected the third callback not to run, got %v", calls)
+	}
+
+	calls = nil
+	if err := d.EmitVetoable("before-delete", "max"); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if len(calls) != 3 {
+		t.Errorf("Expected all callbacks to run, got %v", calls)
+	}
+
+	if err := d.EmitVetoable("unknown", nil); err != nil {
+		t.Errorf("Expected nil without callbacks, got %v", err)
+	}
+}
+
+func TestEmitVetoableStopPropagation(t *testing.T) {
+	d := NewSignalDispatcher()
+
+	var calls []int
+	d.ConnectVetoable("resolve", func(signal Signal, data interface{}) error {
+		calls = append(calls, 1)
+		return fmt.Errorf("handled by cache: %w", ErrStopPropagation)
+	})
+	d.ConnectVetoable("resolve", func(signal Signal, data interface{}) error {
+		calls = append(calls, 2)
+		return nil
+	})
+
+	if err := d.EmitVetoable("resolve", nil); err != nil {
+		t.Errorf("Expected nil after stop, got %v", err)
+	}
+	if len(calls) != 1 || calls[0] != 1 {
+		t.Errorf("Expected the second callback not to run, got %v", calls)
+	}
+}
+
+func TestEmitVetoablePriority(t *testing.T) {
+	d := NewSignalDispatcher()
+
+	var calls []string
+	errInvalid := errors.New("invalid")
+	d.ConnectVetoable("before-save", func(signal Signal, data interface{}) error {
+		calls = append(calls, "optional")
+		return nil
+	})
+	d.ConnectVetoableWithPriority("before-save", -1, func(signal Signal, data interface{}) error {
+		calls = append(calls, "audit")
+		return nil
+	})
+	d.ConnectVetoableWithPriority("before-save", 10, func(signal Signal, data interface{}) error {
+		calls = append(calls, "critical")
+		if data == "invalid" {
+			return errInvalid
+		}
+		return nil
+	})
+	d.ConnectVetoable("before-save", func(signal Signal, data interface{}) error {
+		calls = append(calls, "optional2")
+		return nil
+	})
+	d.ConnectVetoableWithPriority("before-save", 5, func(signal Signal, data interface{}) error {
+		calls = append(calls, "important")
+		return nil
+	})
+
+	if err := d.EmitVetoable("before-save", "valid"); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	expected := []string{"critical", "important", "optional", "optional2", "audit"}
+	if !slices.Equal(calls, expected) {
+		t.Errorf("Expected %v, got %v", expected, calls)
+	}
+
+	calls = nil
+	if err := d.EmitVetoable("before-save", "invalid"); !errors.Is(err, errInvalid) {
+		t.Errorf("Expected veto, got %v", err)
+	}
+	if !slices.Equal(calls, []string{"critical"}) {
+		t.Errorf("Expected lower priority callbacks not to run, got %v", calls)
+	}
+}
+
+func TestEmitCollectMap(t *testing.T) {
+	d := NewSignalDispatcher()
+
+	err := d.ConnectNamed("collect-capabilities", "export", func(signal Signal, data interface{}) interface{} {
+		return []string{"csv", "json"}
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	err = d.ConnectNamed("collect-capabilities", "auth", func(signal Signal, data interface{}) interface{} {
+		return "oauth"
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	results := d.EmitCollectMap("collect-capabilities", nil)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %v", results)
+	}
+	if formats, ok := results["export"].([]string); !ok || len(formats) != 2 {
+		t.Errorf("Unexpected export result: %v", results["export"])
+	}
+	if results["auth"] != "oauth" {
+		t.Errorf("Unexpected auth result: %v", results["auth"])
+	}
+
+	err = d.ConnectNamed("collect-capabilities", "auth", func(signal Signal, data interface{}) interface{} {
+		return nil
+	})
+	if err == nil {
+		t.Errorf("Expected an error for a duplicate name")
+	}
+
+	if results := d.EmitCollectMap("unknown", nil); len(results) != 0 {
+		t.Errorf("Expected no results, got %v", results)
+	}
+}
+
+func TestTypeDispatcher(t *testing.T) {
+	type userCreated struct{ Name string }
+	type orderPlaced struct{ ID int }
+
+	d := NewTypeDispatcher()
+	var lock sync.Mutex
+	var users []string
+	orders := 0
+	ConnectType(d, func(e userCreated) {
+		lock.Lock()
+		defer lock.Unlock()
+		users = append(users, e.Name)
+	})
+	ConnectType(d, func(e orderPlaced) {
+		lock.Lock()
+		defer lock.Unlock()
+		orders += e.ID
+	})
+
+	d.Emit(userCreated{Name: "Max"})
+	d.Emit(orderPlaced{ID: 7})
+	d.Emit(&userCreated{Name: "Pointer"})
+	d.Emit("unrelated")
+
+	if len(users) != 1 || users[0] != "Max" {
+		t.Errorf("Expected only the userCreated handler to receive Max, got %v", users)
+	}
+	if orders != 7 {
+		t.Errorf("Expected the orderPlaced handler to receive 7, got %d", orders)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	d := NewSignalDispatcher()
+
+	ch, unsubscribe := d.Subscribe("tick")
+	d.Emit("tick", 1)
+	d.Emit("tick", 2)
+
+	for _, expected := range []int{1, 2} {
+		select {
+		case v := <-ch:
+			if v != expected {
+				t.Errorf("Expected %d, got %v", expected, v)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %d to be delivered", expected)
+		}
+	}
+
+	unsubscribe()
+	unsubscribe()
+	d.Emit("tick", 3)
+	if v, ok := <-ch; ok {
+		t.Errorf("Expected closed channel, got %v", v)
+	}
+	if n := len(d.listenersOf("tick")); n != 0 {
+		t.Errorf("Expected listener to be removed, got %d", n)
+	}
+
+	t.Run("Full buffer", func(t *testing.T) {
+		d := NewSignalDispatcher()
+		d.SubscriptionBuffer = 1
+		_, unsubscribe := d.Subscribe("tick")
+		d.Emit("tick", 1)
+
+		emitted := make(chan struct{})
+		go func() {
+			d.Emit("tick", 2)
+			close(emitted)
+		}()
+		select {
+		case <-emitted:
+			t.Fatal("Expected Emit to block on a full buffer")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		unsubscribe()
+		select {
+		case <-emitted:
+		case <-time.After(time.Second):
+			t.Fatal("Expected unsubscribe to release the blocked Emit")
+		}
+	})
+}
+
+func TestEmitTraced(t *testing.T) {
+	d := NewSignalDispatcher()
+
+	var lock sync.Mutex
+	edges := []string{}
+	d.TraceHook = func(traceID string, parent Signal, child Signal) {
+		lock.Lock()
+		defer lock.Unlock()
+		edges = append(edges, traceID+":"+string(parent)+">"+string(child))
+	}
+
+	var received *TraceEnvelope
+	d.Connect("order-created", func(signal Signal, data interface{}) {
+		envelope := data.(*TraceEnvelope)
+		envelope.Emit("invoice-created", "invoice-1")
+	})
+	d.Connect("invoice-created", func(signal Signal, data interface{}) {
+		received = data.(*TraceEnvelope)
+	})
+
+	d.EmitTraced("order-created", "order-1", "trace-42")
+
+	if received == nil || received.TraceID != "trace-42" || received.Data != "invoice-1" {
+		t.Fatalf("Expected child emit to carry the trace ID, got %+v", received)
+	}
+	if len(edges) != 2 || edges[0] != "trace-42:>order-created" || edges[1] != "trace-42:order-created>invoice-created" {
+		t.Errorf("Unexpected edges: %v", edges)
+	}
+}
@@ -0,0 +1,36 @@
+package signal
+
+// EmitSticky stores the data as the last value of the signal and emits it
+// like Emit. Callbacks connected later with ConnectSticky receive the stored
+// value immediately. The value is stored and the callbacks to emit to are
+// taken under one lock, so a callback connected concurrently receives the
+// value once, either from ConnectSticky or from the emit.
+func (d *SignalDispatcher) EmitSticky(signal Signal, data interface{}, opts ...EmitOpt) {
+	d.lock.Lock()
+	if d.sticky == nil {
+		d.sticky = make(map[Signal]interface{})
+	}
+	d.sticky[signal] = data
+	listeners := d.listenersLocked(signal)
+	d.lock.Unlock()
+
+	d.emitTo(signal, data, listeners, opts)
+}
+
+// ConnectSticky registers a callback like Connect. If a value was emitted
+// with EmitSticky before, the callback is called with the most recent value
+// before ConnectSticky returns.
+func (d *SignalDispatcher) ConnectSticky(signal Signal, callback Callback) {
+	d.lock.Lock()
+	data, exists := d.sticky[signal]
+	if _, ok := d.listeners[signal]; !ok {
+		d.listeners[signal] = []listener{}
+	}
+	d.listeners[signal] = append(d.listeners[signal], newListener(callback))
+	wrapped := chain(d.middleware, callback)
+	d.lock.Unlock()
+
+	if exists {
+		wrapped(signal, data)
+	}
+}
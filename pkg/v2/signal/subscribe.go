@@ -0,0 +1,52 @@
+package signal
+
+import (
+	"sync"
+)
+
+// DefaultSubscriptionBuffer is the buffer size of the channels returned by
+// Subscribe if SubscriptionBuffer is not set.
+const DefaultSubscriptionBuffer = 16
+
+// Subscribe returns a channel receiving the data of each emitted signal and a
+// function to unsubscribe. Unsubscribing stops the delivery and closes the
+// channel, it is safe to call it more than once.
+//
+// Emits block once the buffer of the channel is full, until the data is
+// received or the subscription is cancelled.
+func (d *SignalDispatcher) Subscribe(signal Signal) (<-chan interface{}, func()) {
+	size := d.SubscriptionBuffer
+	if size == 0 {
+		size = DefaultSubscriptionBuffer
+	}
+	ch := make(chan interface{}, size)
+	done := make(chan struct{})
+
+	var lock sync.RWMutex
+	closed := false
+	l := newListener(func(signal Signal, data interface{}) {
+		lock.RLock()
+		defer lock.RUnlock()
+		if closed {
+			return
+		}
+		select {
+		case ch <- data:
+		case <-done:
+		}
+	})
+	d.connect(signal, l)
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			d.disconnect(signal, l.id)
+			close(done)
+			lock.Lock()
+			defer lock.Unlock()
+			closed = true
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
@@ -0,0 +1,31 @@
+package signal
+
+// ConnectTagged registers a callback for a given signal as member of a group,
+// e.g. a tenant or module. The callback is called by Emit and by EmitTagged
+// with the same tag.
+func (d *SignalDispatcher) ConnectTagged(signal Signal, tag string, callback Callback) {
+	l := newListener(callback)
+	l.tag = tag
+	d.connect(signal, l)
+}
+
+// EmitTagged emits a signal like Emit, but only to the callbacks connected
+// with ConnectTagged and the same tag. An empty tag emits to all callbacks.
+func (d *SignalDispatcher) EmitTagged(signal Signal, tag string, data interface{}, opts ...EmitOpt) {
+	d.emit(signal, tag, data, opts)
+}
+
+// withTag returns the listeners with the given tag, or all listeners if the
+// tag is empty.
+func withTag(listeners []listener, tag string) []listener {
+	if tag == "" {
+		return listeners
+	}
+	tagged := []listener{}
+	for _, l := range listeners {
+		if l.tag == tag {
+			tagged = append(tagged, l)
+		}
+	}
+	return tagged
+}
@@ -0,0 +1,36 @@
+package signal
+
+// TraceEnvelope wraps the data passed to callbacks by EmitTraced. Callbacks
+// emitting further signals use Emit of the envelope to propagate the trace ID.
+type TraceEnvelope struct {
+	TraceID string
+	Signal  Signal
+	Data    interface{}
+
+	dispatcher *SignalDispatcher
+}
+
+// Emit emits a signal with the data wrapped in a *TraceEnvelope carrying the
+// same trace ID, recording the signal of the envelope as parent.
+func (e *TraceEnvelope) Emit(signal Signal, data interface{}, opts ...EmitOpt) {
+	e.dispatcher.emitTraced(e.TraceID, e.Signal, signal, data, opts)
+}
+
+// EmitTraced emits a signal with the data wrapped in a *TraceEnvelope, so the
+// signals emitted by the callbacks can be correlated by the trace ID, see
+// TraceHook.
+func (d *SignalDispatcher) EmitTraced(signal Signal, data interface{}, traceID string, opts ...EmitOpt) {
+	d.emitTraced(traceID, "", signal, data, opts)
+}
+
+func (d *SignalDispatcher) emitTraced(traceID string, parent Signal, signal Signal, data interface{}, opts []EmitOpt) {
+	if d.TraceHook != nil {
+		d.TraceHook(traceID, parent, signal)
+	}
+	d.Emit(signal, &TraceEnvelope{
+		TraceID:    traceID,
+		Signal:     signal,
+		Data:       data,
+		dispatcher: d,
+	}, opts...)
+}
@@ -0,0 +1,42 @@
+package signal
+
+import (
+	"reflect"
+	"sync"
+)
+
+// TypeDispatcher routes data to the handlers registered for its concrete type
+// instead of a signal name, e.g. for events defined as structs.
+type TypeDispatcher struct {
+	lock      sync.Mutex
+	listeners map[reflect.Type][]listener
+}
+
+// NewTypeDispatcher creates a new instance of TypeDispatcher
+func NewTypeDispatcher() *TypeDispatcher {
+	return &TypeDispatcher{listeners: make(map[reflect.Type][]listener)}
+}
+
+// ConnectType registers a handler for data of the type T. The type must match
+// exactly, a handler for UserCreated does not receive *UserCreated.
+func ConnectType[T any](d *TypeDispatcher, handler func(data T)) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.listeners[t] = append(d.listeners[t], newListener(func(signal Signal, data interface{}) {
+		handler(data.(T))
+	}))
+}
+
+// Emit calls the handlers registered for the concrete type of the data in
+// parallel and waits for them to finish.
+func (d *TypeDispatcher) Emit(data interface{}) {
+	t := reflect.TypeOf(data)
+	d.lock.Lock()
+	listeners := append([]listener{}, d.listeners[t]...)
+	d.lock.Unlock()
+	if len(listeners) == 0 {
+		return
+	}
+	run(Signal(t.String()), data, listeners, true, 0, nil)
+}
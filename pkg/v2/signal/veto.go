@@ -0,0 +1,68 @@
+package signal
+
+import (
+	"errors"
+	"slices"
+)
+
+// ErrStopPropagation can be returned by a callback of EmitVetoable to skip the
+// remaining callbacks without vetoing. It only applies to EmitVetoable, which
+// calls the callbacks one after another; the callbacks of Emit run
+// independently of each other and can not stop each other.
+var ErrStopPropagation = errors.New("stop propagation")
+
+// CallbackVeto is a callback of a vetoable signal. Returning an error vetoes
+// the action announced by the signal.
+type CallbackVeto func(signal Signal, data interface{}) error
+
+// vetoer is a callback connected with ConnectVetoableWithPriority.
+type vetoer struct {
+	priority int
+	callback CallbackVeto
+}
+
+// ConnectVetoable registers a callback for a signal emitted with
+// EmitVetoable, e.g. "before-delete". It has the priority 0.
+func (d *SignalDispatcher) ConnectVetoable(signal Signal, callback CallbackVeto) {
+	d.ConnectVetoableWithPriority(signal, 0, callback)
+}
+
+// ConnectVetoableWithPriority registers a callback for a signal emitted with
+// EmitVetoable. Callbacks with a higher priority are called first, so a
+// critical validator can veto before optional ones run. Callbacks with the
+// same priority are called in the order they were connected.
+func (d *SignalDispatcher) ConnectVetoableWithPriority(signal Signal, priority int, callback CallbackVeto) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.vetoers == nil {
+		d.vetoers = make(map[Signal][]vetoer)
+	}
+	vetoers := d.vetoers[signal]
+	i := len(vetoers)
+	for i > 0 && vetoers[i-1].priority < priority {
+		i--
+	}
+	d.vetoers[signal] = slices.Insert(vetoers, i, vetoer{priority: priority, callback: callback})
+}
+
+// EmitVetoable calls the callbacks connected with ConnectVetoable one after
+// another, highest priority first and in the order they were connected
+// otherwise. It stops at the first callback returning an error and returns
+// that error as the veto. The action may proceed if EmitVetoable returns nil.
+// A callback returning ErrStopPropagation stops the remaining callbacks, but
+// EmitVetoable returns nil.
+func (d *SignalDispatcher) EmitVetoable(signal Signal, data interface{}) error {
+	d.lock.Lock()
+	vetoers := slices.Clone(d.vetoers[signal])
+	d.lock.Unlock()
+
+	for _, v := range vetoers {
+		if err := v.callback(signal, data); err != nil {
+			if errors.Is(err, ErrStopPropagation) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}